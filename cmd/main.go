@@ -1,11 +1,19 @@
 package main
 
 import (
+	"os"
+
 	"github.com/shafaalafghany/segokuning-social-app/config"
 	"github.com/shafaalafghany/segokuning-social-app/internal/app"
 )
 
 func main() {
 	cfg := config.NewConfig()
+
+	if len(os.Args) > 1 && os.Args[1] == "reconcile-counters" {
+		app.ReconcileCounters(cfg, os.Args[2:])
+		return
+	}
+
 	app.Run(cfg)
 }
@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +15,11 @@ type Configuration struct {
 	Postgres PostgresConfig
 	Server   ServerConfig
 	S3       S3Config
+	Job      JobConfig
+	Post     PostConfig
+	Tag      TagConfig
+	List     ListConfig
+	Feed     FeedConfig
 }
 
 type ServerConfig struct {
@@ -40,6 +48,65 @@ type S3Config struct {
 	Region     string
 }
 
+type JobConfig struct {
+	SuggestionInterval   time.Duration
+	SuggestionBatchSize  int
+	SuggestionLimit      int
+	CounterInterval      time.Duration
+	CounterBatchSize     int
+	PostPurgeInterval    time.Duration
+	PostPurgeBatchSize   int
+	TagPruneInterval     time.Duration
+	PostPublishInterval  time.Duration
+	PostPublishBatchSize int
+	// PostPublishMaxAttempts is how often publishing a scheduled post is
+	// tried; PostPublishRetryBackoff is the wait after the first failure,
+	// doubled after every further one
+	PostPublishMaxAttempts  int
+	PostPublishRetryBackoff time.Duration
+	DraftExpireInterval     time.Duration
+	DraftExpireBatchSize    int
+}
+
+type PostConfig struct {
+	RestoreWindow  time.Duration
+	MaxAttachments int
+	// AllowedTags and AllowedAttributes override the HTML sanitizer
+	// allowlist; attributes are "tag:attribute" pairs
+	AllowedTags       []string
+	AllowedAttributes []string
+	// FanoutLimit is the friend count above which new posts are not copied
+	// to the friends' home timelines
+	FanoutLimit int
+	// DraftTTL is how long a draft is kept after it was last saved
+	DraftTTL time.Duration
+}
+
+type TagConfig struct {
+	// TrendingWindows are the windows trending tags can be asked for, keyed
+	// by how they are written in the request, e.g. "24h" or "7d"
+	TrendingWindows map[string]time.Duration
+	// RecentWindow is how far back the caller's own tags are boosted in
+	// autocomplete
+	RecentWindow time.Duration
+}
+
+type ListConfig struct {
+	// CountLimit is how many matches list endpoints count before reporting
+	// the total as an estimate
+	CountLimit int
+}
+
+type FeedConfig struct {
+	// weights of the ranked feed signals, see rank.Score
+	RankRecencyWeight  float64
+	RankCommentWeight  float64
+	RankAffinityWeight float64
+	RankHalfLife       time.Duration
+	// RankCandidates is how many of the newest posts the ranked feed scores
+	RankCandidates int
+}
+
 func NewConfig() *Configuration {
 	if os.Getenv("ENV") != "production" {
 		if godotenv.Load() != nil {
@@ -72,7 +139,101 @@ func NewConfig() *Configuration {
 			BucketName: os.Getenv("S3_BUCKET_NAME"),
 			Region:     os.Getenv("S3_REGION"),
 		},
+		Job: JobConfig{
+			SuggestionInterval:      getDuration("JOB_SUGGESTION_INTERVAL", time.Hour),
+			SuggestionBatchSize:     getInt("JOB_SUGGESTION_BATCH_SIZE", 100),
+			SuggestionLimit:         getInt("JOB_SUGGESTION_LIMIT", 50),
+			CounterInterval:         getDuration("JOB_COUNTER_INTERVAL", 6*time.Hour),
+			CounterBatchSize:        getInt("JOB_COUNTER_BATCH_SIZE", 500),
+			PostPurgeInterval:       getDuration("JOB_POST_PURGE_INTERVAL", time.Hour),
+			PostPurgeBatchSize:      getInt("JOB_POST_PURGE_BATCH_SIZE", 100),
+			TagPruneInterval:        getDuration("JOB_TAG_PRUNE_INTERVAL", 24*time.Hour),
+			PostPublishInterval:     getDuration("JOB_POST_PUBLISH_INTERVAL", time.Minute),
+			PostPublishBatchSize:    getInt("JOB_POST_PUBLISH_BATCH_SIZE", 100),
+			PostPublishMaxAttempts:  getInt("JOB_POST_PUBLISH_MAX_ATTEMPTS", 5),
+			PostPublishRetryBackoff: getDuration("JOB_POST_PUBLISH_RETRY_BACKOFF", time.Minute),
+			DraftExpireInterval:     getDuration("JOB_DRAFT_EXPIRE_INTERVAL", time.Hour),
+			DraftExpireBatchSize:    getInt("JOB_DRAFT_EXPIRE_BATCH_SIZE", 500),
+		},
+		Post: PostConfig{
+			RestoreWindow:     getDuration("POST_RESTORE_WINDOW", 30*24*time.Hour),
+			MaxAttachments:    getInt("POST_MAX_ATTACHMENTS", 4),
+			AllowedTags:       getList("POST_ALLOWED_TAGS"),
+			AllowedAttributes: getList("POST_ALLOWED_ATTRIBUTES"),
+			FanoutLimit:       getInt("POST_FANOUT_LIMIT", 5000),
+			DraftTTL:          getDuration("POST_DRAFT_TTL", 30*24*time.Hour),
+		},
+		Tag: TagConfig{
+			TrendingWindows: getWindows("TAG_TRENDING_WINDOWS", "1h,24h,7d"),
+			RecentWindow:    getDuration("TAG_RECENT_WINDOW", 30*24*time.Hour),
+		},
+		List: ListConfig{
+			CountLimit: getInt("LIST_COUNT_LIMIT", 10000),
+		},
+		Feed: FeedConfig{
+			RankRecencyWeight:  getFloat("FEED_RANK_RECENCY_WEIGHT", 1),
+			RankCommentWeight:  getFloat("FEED_RANK_COMMENT_WEIGHT", 0.3),
+			RankAffinityWeight: getFloat("FEED_RANK_AFFINITY_WEIGHT", 0.5),
+			RankHalfLife:       getDuration("FEED_RANK_HALF_LIFE", 6*time.Hour),
+			RankCandidates:     getInt("FEED_RANK_CANDIDATES", 500),
+		},
 	}
 
 	return &config
 }
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// getWindows parses a comma separated list of durations, which unlike
+// time.ParseDuration may also be written in days, e.g. "7d".
+func getWindows(key, fallback string) map[string]time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		value = fallback
+	}
+
+	windows := make(map[string]time.Duration)
+	for _, window := range strings.Split(value, ",") {
+		window = strings.TrimSpace(window)
+		if days, ok := strings.CutSuffix(window, "d"); ok {
+			if n, err := strconv.Atoi(days); err == nil && n > 0 {
+				windows[window] = time.Duration(n) * 24 * time.Hour
+			}
+			continue
+		}
+		if duration, err := time.ParseDuration(window); err == nil && duration > 0 {
+			windows[window] = duration
+		}
+	}
+	return windows
+}
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS follows (
+  follower_id UUID REFERENCES users(id) NOT NULL,
+  following_id UUID REFERENCES users(id) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (follower_id, following_id)
+);
@@ -0,0 +1 @@
+DROP INDEX follows_following_id;
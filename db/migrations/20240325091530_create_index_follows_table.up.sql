@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS follows_following_id ON follows(following_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+  DROP COLUMN IF EXISTS follower_count,
+  DROP COLUMN IF EXISTS following_count;
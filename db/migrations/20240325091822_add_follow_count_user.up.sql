@@ -0,0 +1,3 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS follower_count INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS following_count INTEGER NOT NULL DEFAULT 0;
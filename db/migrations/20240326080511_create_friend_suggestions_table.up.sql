@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS friend_suggestions (
+  user_id UUID REFERENCES users(id) NOT NULL,
+  suggested_id UUID REFERENCES users(id) NOT NULL,
+  mutual_count INTEGER NOT NULL DEFAULT 0,
+  shared_tag_count INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, suggested_id)
+);
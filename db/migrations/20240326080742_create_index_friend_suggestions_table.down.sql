@@ -0,0 +1 @@
+DROP INDEX friend_suggestions_rank;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS friend_suggestions_rank ON friend_suggestions(user_id, mutual_count DESC, shared_tag_count DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS friend_list_members;
+DROP TABLE IF EXISTS friend_lists;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS friend_lists (
+  id UUID PRIMARY KEY NOT NULL,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  name VARCHAR NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS friend_list_members (
+  list_id UUID REFERENCES friend_lists(id) ON DELETE CASCADE NOT NULL,
+  friend_id UUID REFERENCES users(id) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (list_id, friend_id)
+);
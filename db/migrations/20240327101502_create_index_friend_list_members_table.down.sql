@@ -0,0 +1 @@
+DROP INDEX friend_list_members_friend_id;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS friend_list_members_friend_id ON friend_list_members(friend_id);
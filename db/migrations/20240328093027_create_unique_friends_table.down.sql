@@ -0,0 +1 @@
+ALTER TABLE friends DROP CONSTRAINT IF EXISTS friends_user_id_friend_id_key;
@@ -0,0 +1,6 @@
+DELETE FROM friends a USING friends b
+WHERE a.ctid < b.ctid AND a.user_id = b.user_id AND a.friend_id = b.friend_id;
+
+ALTER TABLE friends ADD CONSTRAINT friends_user_id_friend_id_key UNIQUE (user_id, friend_id);
+
+UPDATE users SET friend_count = (SELECT count(friend_id) FROM friends WHERE friends.user_id = users.id);
@@ -0,0 +1,11 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS post_revisions (
+  id UUID PRIMARY KEY NOT NULL,
+  post_id UUID REFERENCES posts(id) NOT NULL,
+  content VARCHAR NOT NULL,
+  tags VARCHAR[] NOT NULL,
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS post_revisions_post_id ON post_revisions(post_id, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS posts_deleted_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS posts_deleted_at ON posts(deleted_at) WHERE deleted_at IS NOT NULL;
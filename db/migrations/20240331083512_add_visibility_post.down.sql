@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS posts_public_created_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS visibility;
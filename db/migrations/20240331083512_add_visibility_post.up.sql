@@ -0,0 +1,4 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS visibility VARCHAR NOT NULL DEFAULT 'friends'
+  CHECK (visibility IN ('public', 'friends', 'friends-of-friends', 'only-me'));
+
+CREATE INDEX IF NOT EXISTS posts_public_created_at ON posts(created_at) WHERE visibility = 'public';
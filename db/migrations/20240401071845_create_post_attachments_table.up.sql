@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS images (
+  id UUID PRIMARY KEY NOT NULL,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  url VARCHAR NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS post_attachments (
+  post_id UUID REFERENCES posts(id) NOT NULL,
+  image_id UUID REFERENCES images(id) NOT NULL,
+  alt_text VARCHAR NOT NULL DEFAULT '',
+  position INTEGER NOT NULL,
+  PRIMARY KEY (post_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS images_user_id ON images(user_id);
+CREATE INDEX IF NOT EXISTS post_attachments_image_id ON post_attachments(image_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS reactions (
+  target_type VARCHAR NOT NULL CHECK (target_type IN ('post', 'comment')),
+  target_id UUID NOT NULL,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  type VARCHAR NOT NULL CHECK (type IN ('like', 'love', 'haha', 'wow', 'sad', 'angry')),
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (target_type, target_id, user_id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS posts_reshare_of;
+ALTER TABLE posts DROP COLUMN IF EXISTS reshare_count;
+ALTER TABLE posts DROP COLUMN IF EXISTS reshare_of;
@@ -0,0 +1,6 @@
+-- reshare_of has no foreign key: purged originals leave reshares behind,
+-- which then render the original as a tombstone
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reshare_of UUID;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS reshare_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS posts_reshare_of ON posts(reshare_of) WHERE reshare_of IS NOT NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS mentions (
+  post_id UUID REFERENCES posts(id) NOT NULL,
+  target_type VARCHAR NOT NULL CHECK (target_type IN ('post', 'comment')),
+  target_id UUID NOT NULL,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (target_type, target_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS mentions_user_id ON mentions(user_id, post_id);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS tag_hourly_counts (
+  tag VARCHAR NOT NULL,
+  bucket TIMESTAMP NOT NULL,
+  post_count INTEGER NOT NULL,
+  PRIMARY KEY (tag, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS tag_hourly_counts_bucket ON tag_hourly_counts(bucket);
+
+INSERT INTO tag_hourly_counts (tag, bucket, post_count)
+SELECT post_tags.tag, date_trunc('hour', posts.created_at), count(DISTINCT posts.id)
+FROM posts
+CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+WHERE posts.visibility = 'public' AND posts.deleted_at IS NULL
+GROUP BY post_tags.tag, date_trunc('hour', posts.created_at)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+-- the tags as originally typed are not kept, so only the index is undone
+DROP INDEX IF EXISTS posts_tags;
@@ -0,0 +1,26 @@
+-- same rules as tag.Normalize: trim, drop a leading #, lower case in NFC,
+-- keep the first occurrence of each tag; tags over 50 characters are cut.
+-- lower() stands in for full case folding, which postgres does not have
+UPDATE posts SET tags = ARRAY(
+  SELECT normalized_tags.tag
+  FROM (
+    SELECT
+      left(btrim(normalize(lower(regexp_replace(btrim(post_tags.tag), '^#', '')), NFC)), 50) AS tag,
+      min(post_tags.position) AS position
+    FROM unnest(posts.tags) WITH ORDINALITY AS post_tags(tag, position)
+    GROUP BY 1
+  ) AS normalized_tags
+  WHERE normalized_tags.tag <> ''
+  ORDER BY normalized_tags.position
+);
+
+DELETE FROM tag_hourly_counts;
+
+INSERT INTO tag_hourly_counts (tag, bucket, post_count)
+SELECT post_tags.tag, date_trunc('hour', posts.created_at), count(DISTINCT posts.id)
+FROM posts
+CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+WHERE posts.visibility = 'public' AND posts.deleted_at IS NULL
+GROUP BY post_tags.tag, date_trunc('hour', posts.created_at);
+
+CREATE INDEX IF NOT EXISTS posts_tags ON posts USING GIN (tags);
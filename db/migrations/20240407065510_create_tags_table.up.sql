@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS tags (
+  name VARCHAR PRIMARY KEY NOT NULL,
+  usage_count INTEGER NOT NULL DEFAULT 0
+);
+
+-- serves the prefix LIKE of tag autocomplete regardless of the collation
+CREATE INDEX IF NOT EXISTS tags_name_pattern ON tags(name varchar_pattern_ops);
+
+INSERT INTO tags (name, usage_count)
+SELECT post_tags.tag, count(DISTINCT posts.id)
+FROM posts
+CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+WHERE posts.deleted_at IS NULL
+GROUP BY post_tags.tag
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_friend_count_id;
+DROP INDEX IF EXISTS users_created_at_id;
+DROP INDEX IF EXISTS posts_created_at_id;
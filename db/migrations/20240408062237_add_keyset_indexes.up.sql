@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS posts_created_at_id ON posts (created_at, id);
+CREATE INDEX IF NOT EXISTS users_created_at_id ON users (created_at, id);
+CREATE INDEX IF NOT EXISTS users_friend_count_id ON users (friend_count, id);
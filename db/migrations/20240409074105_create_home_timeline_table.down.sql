@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS posts_not_fanned_out;
+DROP TABLE IF EXISTS home_timeline;
+ALTER TABLE posts DROP COLUMN IF EXISTS fanned_out;
@@ -0,0 +1,22 @@
+-- posts written before the timeline existed count as fanned out, the
+-- backfill below gives them the same rows a new post would get
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS fanned_out BOOLEAN NOT NULL DEFAULT true;
+
+CREATE TABLE IF NOT EXISTS home_timeline (
+  user_id UUID NOT NULL,
+  post_id UUID NOT NULL,
+  author_id UUID NOT NULL,
+  PRIMARY KEY (user_id, post_id)
+);
+CREATE INDEX IF NOT EXISTS home_timeline_user_author ON home_timeline (user_id, author_id);
+CREATE INDEX IF NOT EXISTS home_timeline_post_id ON home_timeline (post_id);
+CREATE INDEX IF NOT EXISTS posts_not_fanned_out ON posts (user_id, created_at) WHERE NOT fanned_out;
+
+INSERT INTO home_timeline (user_id, post_id, author_id)
+SELECT posts.user_id, posts.id, posts.user_id FROM posts WHERE posts.deleted_at IS NULL
+UNION ALL
+SELECT friends.friend_id, posts.id, posts.user_id
+FROM posts
+JOIN friends ON friends.user_id = posts.user_id
+WHERE posts.deleted_at IS NULL
+ON CONFLICT (user_id, post_id) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scheduled_post_attachments;
+DROP TABLE IF EXISTS scheduled_posts;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS scheduled_posts (
+  id UUID PRIMARY KEY NOT NULL,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  content VARCHAR NOT NULL,
+  tags VARCHAR[] NOT NULL,
+  visibility VARCHAR NOT NULL,
+  publish_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS scheduled_posts_publish_at ON scheduled_posts (publish_at);
+CREATE INDEX IF NOT EXISTS scheduled_posts_user_id ON scheduled_posts (user_id, publish_at);
+
+CREATE TABLE IF NOT EXISTS scheduled_post_attachments (
+  scheduled_post_id UUID REFERENCES scheduled_posts(id) ON DELETE CASCADE NOT NULL,
+  image_id UUID REFERENCES images(id) NOT NULL,
+  alt_text VARCHAR NOT NULL DEFAULT '',
+  position INTEGER NOT NULL,
+  PRIMARY KEY (scheduled_post_id, position)
+);
+CREATE INDEX IF NOT EXISTS scheduled_post_attachments_image_id ON scheduled_post_attachments (image_id);
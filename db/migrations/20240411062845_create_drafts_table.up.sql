@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS drafts (
+  id UUID PRIMARY KEY NOT NULL,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  content VARCHAR NOT NULL DEFAULT '',
+  tags VARCHAR[] NOT NULL DEFAULT '{}',
+  visibility VARCHAR NOT NULL DEFAULT '',
+  attachments JSONB NOT NULL DEFAULT '[]',
+  version BIGINT NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+  updated_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS drafts_user_id ON drafts (user_id, updated_at);
+CREATE INDEX IF NOT EXISTS drafts_updated_at ON drafts (updated_at);
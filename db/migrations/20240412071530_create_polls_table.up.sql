@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS polls (
+  post_id UUID PRIMARY KEY REFERENCES posts(id) NOT NULL,
+  multiple BOOLEAN NOT NULL DEFAULT false,
+  hide_results BOOLEAN NOT NULL DEFAULT false,
+  closes_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+  id UUID PRIMARY KEY NOT NULL,
+  post_id UUID REFERENCES polls(post_id) NOT NULL,
+  label VARCHAR NOT NULL,
+  position INTEGER NOT NULL,
+  UNIQUE (post_id, position)
+);
+
+-- one row per voter keeps a vote and its change atomic, whatever the number
+-- of options picked
+CREATE TABLE IF NOT EXISTS poll_votes (
+  post_id UUID REFERENCES polls(post_id) NOT NULL,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  option_ids UUID[] NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (post_id, user_id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS posts_user_id_created_at_id;
+DROP INDEX IF EXISTS home_timeline_user_created_at;
+ALTER TABLE home_timeline DROP COLUMN IF EXISTS created_at;
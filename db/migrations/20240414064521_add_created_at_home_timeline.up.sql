@@ -0,0 +1,10 @@
+-- the timeline keeps the post's creation time so a feed page is a range scan
+-- over the viewer's rows instead of a join back to posts
+ALTER TABLE home_timeline ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+UPDATE home_timeline SET created_at = posts.created_at
+FROM posts WHERE posts.id = home_timeline.post_id;
+DELETE FROM home_timeline WHERE created_at IS NULL;
+ALTER TABLE home_timeline ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS home_timeline_user_created_at ON home_timeline (user_id, created_at DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS posts_user_id_created_at_id ON posts (user_id, created_at, id);
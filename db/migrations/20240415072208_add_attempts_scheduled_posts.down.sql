@@ -0,0 +1,4 @@
+ALTER TABLE scheduled_posts
+  DROP COLUMN IF EXISTS retry_at,
+  DROP COLUMN IF EXISTS last_error,
+  DROP COLUMN IF EXISTS attempts;
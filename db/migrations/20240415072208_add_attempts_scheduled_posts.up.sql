@@ -0,0 +1,4 @@
+ALTER TABLE scheduled_posts
+  ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS last_error VARCHAR,
+  ADD COLUMN IF NOT EXISTS retry_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tag_author_hourly_counts;
@@ -0,0 +1,20 @@
+-- non-public posts are aggregated per author and visibility, the reader's
+-- friendships then decide which of them it may count
+CREATE TABLE IF NOT EXISTS tag_author_hourly_counts (
+  author_id UUID NOT NULL,
+  visibility VARCHAR NOT NULL,
+  tag VARCHAR NOT NULL,
+  bucket TIMESTAMP NOT NULL,
+  post_count INTEGER NOT NULL,
+  PRIMARY KEY (author_id, bucket, visibility, tag)
+);
+
+CREATE INDEX IF NOT EXISTS tag_author_hourly_counts_bucket ON tag_author_hourly_counts(bucket);
+
+INSERT INTO tag_author_hourly_counts (author_id, visibility, tag, bucket, post_count)
+SELECT posts.user_id, posts.visibility, post_tags.tag, date_trunc('hour', posts.created_at), count(DISTINCT posts.id)
+FROM posts
+CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+WHERE posts.visibility <> 'public' AND posts.deleted_at IS NULL
+GROUP BY posts.user_id, posts.visibility, post_tags.tag, date_trunc('hour', posts.created_at)
+ON CONFLICT DO NOTHING;
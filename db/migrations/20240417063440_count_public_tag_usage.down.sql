@@ -0,0 +1,4 @@
+UPDATE tags SET usage_count = (
+  SELECT count(DISTINCT posts.id) FROM posts
+  WHERE posts.tags @> ARRAY[tags.name] AND posts.deleted_at IS NULL
+);
@@ -0,0 +1,5 @@
+-- usage counts are served to anonymous callers, so only public posts count
+UPDATE tags SET usage_count = (
+  SELECT count(DISTINCT posts.id) FROM posts
+  WHERE posts.tags @> ARRAY[tags.name] AND posts.visibility = 'public' AND posts.deleted_at IS NULL
+);
@@ -15,13 +15,17 @@ import (
 	"github.com/shafaalafghany/segokuning-social-app/config"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
 	commentHandler "github.com/shafaalafghany/segokuning-social-app/internal/handler/comment"
+	followHandler "github.com/shafaalafghany/segokuning-social-app/internal/handler/follow"
 	friendHandler "github.com/shafaalafghany/segokuning-social-app/internal/handler/friend"
 	imageHandler "github.com/shafaalafghany/segokuning-social-app/internal/handler/image"
 	postHandler "github.com/shafaalafghany/segokuning-social-app/internal/handler/post"
+	tagHandler "github.com/shafaalafghany/segokuning-social-app/internal/handler/tag"
 	userHandler "github.com/shafaalafghany/segokuning-social-app/internal/handler/user"
+	"github.com/shafaalafghany/segokuning-social-app/internal/job"
 	"github.com/shafaalafghany/segokuning-social-app/internal/repository"
 	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
 	"github.com/shafaalafghany/segokuning-social-app/pkg/logger"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/storage"
 )
 
 func Run(cfg *config.Configuration) {
@@ -45,17 +49,50 @@ func Run(cfg *config.Configuration) {
 	fr := repository.NewFriendRepo(pgx, logger)
 	cr := repository.NewCommentRepo(pgx, logger)
 	pr := repository.NewPostRepo(pgx, logger)
+	flr := repository.NewFollowRepo(pgx, logger)
+	sr := repository.NewSuggestionRepo(pgx, logger)
+	lr := repository.NewFriendListRepo(pgx, logger)
+	ctr := repository.NewCounterRepo(pgx, logger)
+	ir := repository.NewImageRepo(pgx, logger)
+	rr := repository.NewReactionRepo(pgx, logger)
+	tr := repository.NewTagRepo(pgx, logger)
+	spr := repository.NewScheduledPostRepo(pgx, logger)
+	dr := repository.NewDraftRepo(pgx, logger)
+	plr := repository.NewPollRepo(pgx, logger)
+	tx := db.NewTransactor(pgx)
+
+	st, err := storage.NewS3Storage(cfg.S3)
+	if err != nil {
+		log.Fatalf("failed to initialize storage: %v", err)
+	}
 
 	r.Handle("/metrics", promhttp.Handler())
 	r.Route("/v1", func(r chi.Router) {
 		// r.Use(promotheus.PrometheusMiddleware)
 		userHandler.NewUserHandler(r, ur, validate, *cfg, logger)
-		friendHandler.NewFriendHandler(r, ur, fr, validate, *cfg, logger)
-		postHandler.NewPostHandler(r, ur, pr, validate, *cfg, logger)
-		commentHandler.NewCommentHandler(r, fr, cr, pr, validate, *cfg, logger)
-		imageHandler.NewImageHandler(r, *validate, *cfg, logger)
+		friendHandler.NewFriendHandler(r, ur, fr, sr, lr, validate, *cfg, logger)
+		followHandler.NewFollowHandler(r, ur, flr, validate, *cfg, logger)
+		postHandler.NewPostHandler(r, ur, pr, cr, ir, rr, spr, dr, plr, tx, validate, *cfg, logger)
+		commentHandler.NewCommentHandler(r, cr, pr, rr, validate, *cfg, logger)
+		tagHandler.NewTagHandler(r, tr, pr, validate, *cfg, logger)
+		imageHandler.NewImageHandler(r, ir, st, *validate, *cfg, logger)
 	})
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	job.Schedule(jobCtx, logger, cfg.Job.SuggestionInterval,
+		job.NewSuggestionJob(sr, cfg.Job.SuggestionBatchSize, cfg.Job.SuggestionLimit, logger))
+	job.Schedule(jobCtx, logger, cfg.Job.CounterInterval,
+		job.NewCounterJob(ctr, repository.Counters, cfg.Job.CounterBatchSize, false, logger))
+	job.Schedule(jobCtx, logger, cfg.Job.PostPurgeInterval,
+		job.NewPostPurgeJob(pr, st, cfg.Post.RestoreWindow, cfg.Job.PostPurgeBatchSize, logger))
+	job.Schedule(jobCtx, logger, cfg.Job.TagPruneInterval,
+		job.NewTagPruneJob(tr, 2*longestWindow(cfg.Tag.TrendingWindows), logger))
+	job.Schedule(jobCtx, logger, cfg.Job.PostPublishInterval,
+		job.NewPostPublishJob(tx, spr, pr, cfg.Post.FanoutLimit, cfg.Job.PostPublishBatchSize,
+			cfg.Job.PostPublishMaxAttempts, cfg.Job.PostPublishRetryBackoff, logger))
+	job.Schedule(jobCtx, logger, cfg.Job.DraftExpireInterval,
+		job.NewDraftExpireJob(dr, cfg.Post.DraftTTL, cfg.Job.DraftExpireBatchSize, logger))
+
 	s := &http.Server{
 		Addr:    cfg.Server.Port,
 		Handler: r,
@@ -71,6 +108,7 @@ func Run(cfg *config.Configuration) {
 	stopped := make(chan os.Signal, 1)
 	signal.Notify(stopped, os.Interrupt)
 	<-stopped
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -81,3 +119,15 @@ func Run(cfg *config.Configuration) {
 	}
 	fmt.Println("server stopped")
 }
+
+// longestWindow returns the longest trending window; trending compares it
+// with the window before it, so aggregates are kept for twice as long.
+func longestWindow(windows map[string]time.Duration) time.Duration {
+	var longest time.Duration
+	for _, window := range windows {
+		if window > longest {
+			longest = window
+		}
+	}
+	return longest
+}
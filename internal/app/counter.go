@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/shafaalafghany/segokuning-social-app/config"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/internal/job"
+	"github.com/shafaalafghany/segokuning-social-app/internal/repository"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/logger"
+)
+
+// ReconcileCounters runs one on-demand pass of the counter reconciliation job
+// and prints what it found, e.g.
+//
+//	app reconcile-counters -counter=friend_count -dry-run
+func ReconcileCounters(cfg *config.Configuration, args []string) {
+	flags := flag.NewFlagSet("reconcile-counters", flag.ExitOnError)
+	names := flags.String("counter", "", "comma separated counters to reconcile, all when empty")
+	batchSize := flags.Int("batch-size", cfg.Job.CounterBatchSize, "rows checked per batch")
+	dryRun := flags.Bool("dry-run", false, "report drift without correcting it")
+	flags.Parse(args)
+
+	counters, err := selectCounters(*names)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	logger, err := logger.Initialize(*cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize logger: %v", err)
+	}
+
+	pgx := db.NewPsqlDB(cfg)
+	defer pgx.Close()
+
+	cj := job.NewCounterJob(repository.NewCounterRepo(pgx, logger), counters, *batchSize, *dryRun, logger)
+	results, err := cj.Reconcile(context.Background())
+	for _, result := range results {
+		fmt.Printf("%-20s scanned=%d drifted=%d corrected=%d\n", result.Counter, result.Scanned, result.Drifted, result.Corrected)
+	}
+	if err != nil {
+		log.Fatalf("error reconciling counters: %v", err)
+	}
+}
+
+func selectCounters(names string) ([]entity.Counter, error) {
+	if names == "" {
+		return repository.Counters, nil
+	}
+
+	counters := make([]entity.Counter, 0)
+	for _, name := range strings.Split(names, ",") {
+		found := false
+		for _, counter := range repository.Counters {
+			if counter.Name == strings.TrimSpace(name) {
+				counters = append(counters, counter)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown counter %q", name)
+		}
+	}
+
+	return counters, nil
+}
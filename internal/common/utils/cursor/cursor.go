@@ -0,0 +1,90 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Next = "next"
+	Prev = "prev"
+)
+
+var ErrInvalid = errors.New("cursor is not valid")
+
+// Page is a position in a list sorted by a key and the row id. Direction
+// tells whether to read the rows after (Next) or before (Prev) it.
+type Page struct {
+	Direction string
+	Key       string
+	Id        string
+}
+
+// Encode packs a sort key and a row id into an opaque, URL safe cursor.
+func Encode(key, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + "|" + id))
+}
+
+// Decode unpacks a cursor made by Encode. Every row id is a UUID, so a
+// cursor holding anything else is rejected here rather than by the database.
+func Decode(cursor string) (key, id string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalid
+	}
+
+	key, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return "", "", ErrInvalid
+	}
+	// uuid.Parse also takes braced and urn forms, which postgres does not
+	if _, err := uuid.Parse(id); err != nil || len(id) != 36 {
+		return "", "", ErrInvalid
+	}
+
+	return key, id, nil
+}
+
+// EncodeTime is Encode for rows ordered by a timestamp.
+func EncodeTime(t time.Time, id string) string {
+	return Encode(t.Format(time.RFC3339Nano), id)
+}
+
+// DecodeTime is Decode for cursors made by EncodeTime.
+func DecodeTime(cursor string) (time.Time, string, error) {
+	key, id, err := Decode(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, key)
+	if err != nil {
+		return time.Time{}, "", ErrInvalid
+	}
+
+	return t, id, nil
+}
+
+// EncodePage is Encode for cursors that can page both ways.
+func EncodePage(page Page) string {
+	return Encode(page.Direction+":"+page.Key, page.Id)
+}
+
+// DecodePage is Decode for cursors made by EncodePage.
+func DecodePage(cursor string) (Page, error) {
+	key, id, err := Decode(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+
+	direction, key, found := strings.Cut(key, ":")
+	if !found || (direction != Next && direction != Prev) {
+		return Page{}, ErrInvalid
+	}
+
+	return Page{Direction: direction, Key: key, Id: id}, nil
+}
@@ -0,0 +1,107 @@
+package mention
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// A mention is written as @ followed by the mentioned user's id.
+var pattern = regexp.MustCompile(`@([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})`)
+
+// Extract returns the distinct user ids mentioned in plain text, in order of
+// first appearance.
+func Extract(text string) []string {
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		id := strings.ToLower(match[1])
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// ExtractHtml is Extract for html content. Only text outside of links is
+// searched.
+func ExtractHtml(content string) []string {
+	var text strings.Builder
+	walkText(content, func(raw string, inLink bool) string {
+		if !inLink {
+			text.WriteString(raw + " ")
+		}
+		return raw
+	})
+
+	return Extract(text.String())
+}
+
+// RenderHtml turns the mentions of the users in names, keyed by user id, into
+// links. Mentions of other users are left as they were written.
+func RenderHtml(content string, names map[string]string) string {
+	if len(names) == 0 {
+		return content
+	}
+
+	return walkText(content, func(raw string, inLink bool) string {
+		if inLink {
+			return raw
+		}
+
+		return pattern.ReplaceAllStringFunc(raw, func(match string) string {
+			id := strings.ToLower(match[1:])
+			name, ok := names[id]
+			if !ok {
+				return match
+			}
+
+			return `<a href="/user/` + id + `" class="mention">@` + html.EscapeString(name) + `</a>`
+		})
+	})
+}
+
+// RenderText is RenderHtml for plain text, which is escaped first so the
+// result is safe to use as html.
+func RenderText(text string, names map[string]string) string {
+	return RenderHtml(html.EscapeString(text), names)
+}
+
+// walkText copies content, passing every raw text token through fn along with
+// whether it sits inside a link.
+func walkText(content string, fn func(raw string, inLink bool) string) string {
+	var (
+		out   strings.Builder
+		links int
+	)
+
+	z := nethtml.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			break
+		}
+
+		raw := string(z.Raw())
+		switch tt {
+		case nethtml.TextToken:
+			out.WriteString(fn(raw, links > 0))
+			continue
+		case nethtml.StartTagToken:
+			if name, _ := z.TagName(); string(name) == "a" {
+				links++
+			}
+		case nethtml.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "a" && links > 0 {
+				links--
+			}
+		}
+		out.WriteString(raw)
+	}
+
+	return out.String()
+}
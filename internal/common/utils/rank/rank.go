@@ -0,0 +1,61 @@
+package rank
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Weights tune how much each signal adds to a post's score. HalfLife is the
+// age at which the recency signal has dropped to half.
+type Weights struct {
+	Recency  float64
+	Comments float64
+	Affinity float64
+	HalfLife time.Duration
+}
+
+// Candidate holds the signals of a post that may be shown in the feed.
+// Affinity is how often the viewer reacted to or commented on the author's
+// posts.
+type Candidate struct {
+	ID        string
+	CreatedAt time.Time
+	Comments  int64
+	Affinity  int64
+}
+
+// Score rates a candidate as seen at now. Recency decays exponentially with
+// age, while comments and affinity grow logarithmically so a single busy post
+// or author can't push out everything else. Posts from the future count as
+// brand new.
+func Score(c Candidate, now time.Time, w Weights) float64 {
+	recency := 1.0
+	if age := now.Sub(c.CreatedAt); age > 0 && w.HalfLife > 0 {
+		recency = math.Exp2(-float64(age) / float64(w.HalfLife))
+	}
+
+	return w.Recency*recency +
+		w.Comments*math.Log1p(float64(max(c.Comments, 0))) +
+		w.Affinity*math.Log1p(float64(max(c.Affinity, 0)))
+}
+
+// Sort orders candidates by score, highest first. Ties go to the newer post
+// and then to the higher id, so the order never depends on the input order.
+func Sort(candidates []Candidate, now time.Time, w Weights) {
+	scores := make(map[string]float64, len(candidates))
+	for _, c := range candidates {
+		scores[c.ID] = Score(c, now, w)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] > scores[b.ID]
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+}
@@ -0,0 +1,155 @@
+package rank
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestScoreRecency(t *testing.T) {
+	w := Weights{Recency: 1, HalfLife: time.Hour}
+
+	tests := []struct {
+		name string
+		age  time.Duration
+		want float64
+	}{
+		{"new post", 0, 1},
+		{"one half-life", time.Hour, 0.5},
+		{"two half-lives", 2 * time.Hour, 0.25},
+		{"half a half-life", 30 * time.Minute, 1 / math.Sqrt2},
+		{"future post counts as new", -time.Hour, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(Candidate{ID: "a", CreatedAt: now.Add(-tt.age)}, now, w)
+			if !near(got, tt.want) {
+				t.Errorf("Score at age %s = %v, want %v", tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreNoHalfLife(t *testing.T) {
+	w := Weights{Recency: 2}
+	got := Score(Candidate{ID: "a", CreatedAt: now.Add(-24 * time.Hour)}, now, w)
+	if !near(got, 2) {
+		t.Errorf("Score without half-life = %v, want 2", got)
+	}
+}
+
+func TestScoreWeights(t *testing.T) {
+	c := Candidate{ID: "a", CreatedAt: now.Add(-time.Hour), Comments: 3, Affinity: 7}
+
+	tests := []struct {
+		name string
+		w    Weights
+		want float64
+	}{
+		{"all zero", Weights{HalfLife: time.Hour}, 0},
+		{"recency only", Weights{Recency: 2, HalfLife: time.Hour}, 1},
+		{"comments only", Weights{Comments: 1, HalfLife: time.Hour}, math.Log(4)},
+		{"affinity only", Weights{Affinity: 1, HalfLife: time.Hour}, math.Log(8)},
+		{"all signals", Weights{Recency: 2, Comments: 0.5, Affinity: 3, HalfLife: time.Hour}, 1 + 0.5*math.Log(4) + 3*math.Log(8)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(c, now, tt.w); !near(got, tt.want) {
+				t.Errorf("Score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreSignals(t *testing.T) {
+	w := Weights{Comments: 1, Affinity: 1}
+
+	tests := []struct {
+		name string
+		c    Candidate
+		want float64
+	}{
+		{"no signals", Candidate{}, 0},
+		{"one comment", Candidate{Comments: 1}, math.Log(2)},
+		{"many comments grow slowly", Candidate{Comments: 99}, math.Log(100)},
+		{"affinity", Candidate{Affinity: 1}, math.Log(2)},
+		{"negative counts are ignored", Candidate{Comments: -5, Affinity: -5}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.c.CreatedAt = now
+			if got := Score(tt.c, now, w); !near(got, tt.want) {
+				t.Errorf("Score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	w := Weights{Recency: 1, Comments: 1, Affinity: 1, HalfLife: time.Hour}
+	candidates := []Candidate{
+		{ID: "old", CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "new", CreatedAt: now},
+		{ID: "discussed", CreatedAt: now.Add(-48 * time.Hour), Comments: 20},
+		{ID: "friend", CreatedAt: now.Add(-2 * time.Hour), Affinity: 10},
+	}
+
+	Sort(candidates, now, w)
+
+	want := []string{"discussed", "friend", "new", "old"}
+	for i, id := range want {
+		if candidates[i].ID != id {
+			t.Fatalf("Sort order = %v, want %v", ids(candidates), want)
+		}
+	}
+}
+
+func TestSortTies(t *testing.T) {
+	// every candidate scores zero, so only the tie-breakers decide
+	w := Weights{}
+	inputs := [][]Candidate{
+		{
+			{ID: "a", CreatedAt: now.Add(-time.Hour)},
+			{ID: "b", CreatedAt: now},
+			{ID: "c", CreatedAt: now},
+		},
+		{
+			{ID: "c", CreatedAt: now},
+			{ID: "a", CreatedAt: now.Add(-time.Hour)},
+			{ID: "b", CreatedAt: now},
+		},
+		{
+			{ID: "b", CreatedAt: now},
+			{ID: "c", CreatedAt: now},
+			{ID: "a", CreatedAt: now.Add(-time.Hour)},
+		},
+	}
+
+	want := []string{"c", "b", "a"}
+	for _, candidates := range inputs {
+		Sort(candidates, now, w)
+		for i, id := range want {
+			if candidates[i].ID != id {
+				t.Fatalf("Sort order = %v, want %v", ids(candidates), want)
+			}
+		}
+	}
+}
+
+func ids(candidates []Candidate) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, c.ID)
+	}
+
+	return out
+}
@@ -0,0 +1,189 @@
+package sanitize
+
+import (
+	"html"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// DefaultTags and DefaultAttributes are used when no allowlist is configured.
+// Attributes are written as "tag:attribute", with "*" matching every tag.
+var (
+	DefaultTags = []string{
+		"p", "br", "b", "strong", "i", "em", "u", "s",
+		"blockquote", "code", "pre", "ul", "ol", "li", "a", "span",
+	}
+	DefaultAttributes = []string{"a:href", "a:title"}
+)
+
+// dropped elements lose their content too, not only their tags
+var dropped = map[string]bool{
+	"script": true, "style": true, "iframe": true, "noscript": true,
+	"noembed": true, "noframes": true, "textarea": true, "title": true,
+	"xmp": true, "plaintext": true, "template": true, "object": true,
+	"embed": true,
+}
+
+var void = map[string]bool{
+	"br": true, "hr": true, "img": true, "wbr": true,
+}
+
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "cite": true, "action": true,
+	"formaction": true, "poster": true, "background": true,
+}
+
+var urlSchemes = map[string]bool{
+	"http": true, "https": true, "mailto": true,
+}
+
+// Policy is an allowlist of HTML tags and attributes. Anything not allowed is
+// removed, event handlers and non http(s)/mailto urls are always removed and
+// links always get rel="nofollow noopener".
+type Policy struct {
+	tags       map[string]bool
+	attributes map[string]map[string]bool
+}
+
+// NewPolicy builds a policy from tag names and "tag:attribute" pairs, falling
+// back to DefaultTags and DefaultAttributes for empty lists.
+func NewPolicy(tags, attributes []string) *Policy {
+	if len(tags) == 0 {
+		tags = DefaultTags
+	}
+	if len(attributes) == 0 {
+		attributes = DefaultAttributes
+	}
+
+	p := &Policy{
+		tags:       make(map[string]bool, len(tags)),
+		attributes: make(map[string]map[string]bool),
+	}
+	for _, tag := range tags {
+		p.tags[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	for _, attribute := range attributes {
+		tag, name, ok := strings.Cut(strings.ToLower(strings.TrimSpace(attribute)), ":")
+		if !ok {
+			continue
+		}
+		if p.attributes[tag] == nil {
+			p.attributes[tag] = make(map[string]bool)
+		}
+		p.attributes[tag][name] = true
+	}
+
+	return p
+}
+
+// Sanitize returns input reduced to the allowed markup. Text is kept and
+// re-escaped, and every allowed tag left open is closed at the end.
+func (p *Policy) Sanitize(input string) string {
+	var (
+		out  strings.Builder
+		open []string
+		skip string
+	)
+
+	z := nethtml.NewTokenizer(strings.NewReader(input))
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			break
+		}
+
+		token := z.Token()
+		if skip != "" {
+			if tt == nethtml.EndTagToken && token.Data == skip {
+				skip = ""
+			}
+			continue
+		}
+
+		switch tt {
+		case nethtml.TextToken:
+			out.WriteString(html.EscapeString(token.Data))
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if dropped[token.Data] {
+				if tt == nethtml.StartTagToken {
+					skip = token.Data
+				}
+				continue
+			}
+			if !p.tags[token.Data] {
+				continue
+			}
+
+			out.WriteString(p.startTag(token))
+			if tt == nethtml.StartTagToken && !void[token.Data] {
+				open = append(open, token.Data)
+			}
+		case nethtml.EndTagToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != token.Data {
+					continue
+				}
+				for len(open) > i {
+					out.WriteString("</" + open[len(open)-1] + ">")
+					open = open[:len(open)-1]
+				}
+				break
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+
+	return out.String()
+}
+
+func (p *Policy) startTag(token nethtml.Token) string {
+	var tag strings.Builder
+	tag.WriteString("<" + token.Data)
+	for _, attr := range token.Attr {
+		if attr.Namespace != "" || !p.allowed(token.Data, attr.Key) {
+			continue
+		}
+		if strings.HasPrefix(attr.Key, "on") || attr.Key == "rel" {
+			continue
+		}
+		if urlAttributes[attr.Key] && !safeUrl(attr.Val) {
+			continue
+		}
+
+		tag.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+
+	if token.Data == "a" {
+		tag.WriteString(` rel="nofollow noopener"`)
+	}
+	tag.WriteString(">")
+
+	return tag.String()
+}
+
+func (p *Policy) allowed(tag, attribute string) bool {
+	return p.attributes[tag][attribute] || p.attributes["*"][attribute]
+}
+
+// safeUrl accepts relative urls and urls with an allowed scheme. Browsers
+// ignore whitespace and control characters inside a scheme, so they are
+// removed before the scheme is read.
+func safeUrl(value string) bool {
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+
+	end := strings.IndexAny(value, ":/?#")
+	if end == -1 || value[end] != ':' {
+		return true
+	}
+
+	return urlSchemes[strings.ToLower(value[:end])]
+}
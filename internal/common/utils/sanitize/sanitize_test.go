@@ -0,0 +1,87 @@
+package sanitize
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text is escaped", `"quoted" & <b>bold</b>`, `&#34;quoted&#34; &amp; <b>bold</b>`},
+		{"disallowed tag keeps its text", `<div>text</div>`, `text`},
+
+		{"script body is removed", `<p>hi<script>alert(1)</script>there</p>`, `<p>hithere</p>`},
+		{"uppercase script", `<SCRIPT>alert(1)</SCRIPT>ok`, `ok`},
+		{"unclosed script drops the rest", `<script>alert(1)`, ``},
+		{"style body is removed", `<style>body{background:url(javascript:alert(1))}</style>ok`, `ok`},
+		{"iframe is removed", `<p><iframe src="https://evil"></iframe>ok</p>`, `<p>ok</p>`},
+		{"img is not allowed by default", `<img src=x onerror=alert(1)>`, ``},
+
+		{"event handler", `<b onclick="alert(1)">x</b>`, `<b>x</b>`},
+		{"mixed case event handler", `<a href="/x" OnMouseOver="alert(1)">x</a>`, `<a href="/x" rel="nofollow noopener">x</a>`},
+		{"event handler on unknown tag", `<svg onload=alert(1)><a>x</a></svg>`, `<a rel="nofollow noopener">x</a>`},
+
+		{"javascript url", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"mixed case javascript url", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"hex entity in scheme", `<a href="jav&#x61;script:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"decimal entity scheme", `<a href="&#106;&#97;&#118;&#97;&#115;&#99;&#114;&#105;&#112;&#116;&#58;alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"tab in scheme", "<a href=\"java\tscript:alert(1)\">x</a>", `<a rel="nofollow noopener">x</a>`},
+		{"encoded tab in scheme", `<a href="java&#x09;script:alert(1)">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"leading control chars", "<a href=\" \x01javascript:alert(1)\">x</a>", `<a rel="nofollow noopener">x</a>`},
+		{"nul in scheme", "<a href=\"java\x00script:alert(1)\">x</a>", `<a rel="nofollow noopener">x</a>`},
+		{"data url", `<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"uppercase data url", `<a href="DATA:text/html,x">x</a>`, `<a rel="nofollow noopener">x</a>`},
+		{"https url", `<a href="https://example.com/a?b=1&c=2">x</a>`, `<a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener">x</a>`},
+		{"relative url", `<a href="/relative">x</a>`, `<a href="/relative" rel="nofollow noopener">x</a>`},
+		{"mailto url", `<a href="mailto:a@b.c">x</a>`, `<a href="mailto:a@b.c" rel="nofollow noopener">x</a>`},
+
+		{"misnested tags", `<b><i>x</b>y`, `<b><i>x</i></b>y`},
+		{"unclosed tag", `<b>unclosed`, `<b>unclosed</b>`},
+		{"stray end tag", `</b>stray`, `stray`},
+		{"script split by script", `<scr<script>ipt>alert(1)</script>`, `ipt&gt;alert(1)`},
+		{"doubled angle brackets", `<<script>script>alert(1)<</script>/script>`, `&lt;/script&gt;`},
+		{"script in comment", `<!--<script>alert(1)</script>-->ok`, `ok`},
+		{"unterminated comment", `<!--unterminated <script>alert(1)</script>`, ``},
+
+		{"rel and target are replaced", `<a href="/x" rel="opener" target="_blank">x</a>`, `<a href="/x" rel="nofollow noopener">x</a>`},
+	}
+
+	p := NewPolicy(nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeConfiguredPolicy(t *testing.T) {
+	// as read from POST_ALLOWED_TAGS and POST_ALLOWED_ATTRIBUTES
+	p := NewPolicy(
+		[]string{"img", " A ", "b"},
+		[]string{"img:src", "img:onerror", "a:href", "a:rel", "a:target", "*:title"},
+	)
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"allowed tag and attribute", `<img src="https://x/y.png">`, `<img src="https://x/y.png">`},
+		{"event handlers stay removed", `<img src="https://x/y.png" onerror="alert(1)">`, `<img src="https://x/y.png">`},
+		{"javascript url on allowed attribute", `<img src="javascript:alert(1)">`, `<img>`},
+		{"data url on allowed attribute", `<img src="data:image/png;base64,AAAA">`, `<img>`},
+		{"rel is always overridden", `<a href="/x" rel="opener" target="_blank" title="t">x</a>`, `<a href="/x" target="_blank" title="t" rel="nofollow noopener">x</a>`},
+		{"defaults no longer apply", `<p><b title="t" class="c">x</b></p>`, `<b title="t">x</b>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const MaxLength = 50
+
+var ErrEmpty = errors.New("tag cannot be empty")
+
+// Normalize returns the canonical form of a tag: surrounding spaces and a
+// leading # are removed, and the rest is case folded in Unicode NFC, so "Go",
+// "go" and "#go" are the same tag.
+func Normalize(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	tag = norm.NFC.String(cases.Fold().String(norm.NFC.String(tag)))
+	return strings.TrimSpace(tag)
+}
+
+// NormalizeAll normalizes tags, keeping the first occurrence of each. It
+// fails when a tag is empty or longer than MaxLength once normalized.
+func NormalizeAll(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = Normalize(tag)
+		if tag == "" {
+			return nil, ErrEmpty
+		}
+		if utf8.RuneCountInString(tag) > MaxLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, MaxLength)
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,6 @@
+package dto
+
+type CommentFilter struct {
+	Limit  int64  `json:"limit" validate:"omitempty,numeric,min=0,max=100" schema:"limit"`
+	Cursor string `json:"cursor" validate:"omitempty" schema:"cursor"`
+}
@@ -5,7 +5,12 @@ import (
 )
 
 type Comment struct {
-	Comment   string      `json:"comment"`
-	Creator   entity.User `json:"creator"`
-	CreatedAt string      `json:"createdAt"`
+	ID      string `json:"commentId,omitempty"`
+	Comment string `json:"comment"`
+	// CommentInHtml is Comment escaped, with its mentions linked
+	CommentInHtml string            `json:"commentInHtml"`
+	Creator       entity.User       `json:"creator"`
+	Reactions     *entity.Reactions `json:"reactions,omitempty"`
+	Mentions      []entity.Mention  `json:"mentions,omitempty"`
+	CreatedAt     string            `json:"createdAt"`
 }
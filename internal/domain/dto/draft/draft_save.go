@@ -0,0 +1,18 @@
+package dto
+
+import dtopost "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+
+// DraftSave is the body of a draft save. Fields may be incomplete; they are
+// validated as a dtopost.PostCreate when the draft is published. Version is
+// the version of the draft being saved over and is ignored on create.
+type DraftSave struct {
+	PostInHtml  string                     `json:"postInHtml" validate:"max=500"`
+	Tags        []string                   `json:"tags" validate:"omitempty,dive,min=1"`
+	Visibility  string                     `json:"visibility" validate:"omitempty,oneof=public friends friends-of-friends only-me"`
+	Attachments []dtopost.AttachmentCreate `json:"attachments" validate:"omitempty,dive"`
+	Version     int64                      `json:"version" validate:"omitempty,min=1"`
+}
+
+type DraftPublish struct {
+	Version int64 `json:"version" validate:"required,min=1"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type FollowData struct {
+	UserId string `json:"userId" validate:"required"`
+}
+
+type FollowFilter struct {
+	UserId string `json:"userId" validate:"omitempty" schema:"userId"`
+	Limit  int64  `json:"limit" validate:"omitempty,numeric,min=0" schema:"limit"`
+	Offset int64  `json:"offset" validate:"omitempty,numeric,min=0" schema:"offset"`
+}
@@ -3,3 +3,9 @@ package dto
 type FriendData struct {
 	UserId string `json:"userId" validate:"required"`
 }
+
+type MutualFriendFilter struct {
+	UserId string `json:"userId" validate:"required" schema:"userId"`
+	Limit  int64  `json:"limit" validate:"omitempty,numeric,min=0" schema:"limit"`
+	Offset int64  `json:"offset" validate:"omitempty,numeric,min=0" schema:"offset"`
+}
@@ -0,0 +1,5 @@
+package dto
+
+type FriendListCreate struct {
+	Name string `json:"name" validate:"required,min=1,max=30"`
+}
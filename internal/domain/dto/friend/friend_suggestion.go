@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/shafaalafghany/segokuning-social-app/internal/entity"
+
+type SuggestionFilter struct {
+	Limit  int64 `json:"limit" validate:"omitempty,numeric,min=0" schema:"limit"`
+	Offset int64 `json:"offset" validate:"omitempty,numeric,min=0" schema:"offset"`
+}
+
+type Suggestion struct {
+	entity.User
+	MutualFriendCount int64 `json:"mutualFriendCount"`
+	SharedTagCount    int64 `json:"sharedTagCount"`
+}
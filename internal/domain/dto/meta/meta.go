@@ -1,7 +1,12 @@
 package dto
 
 type Meta struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
-	Total  int64 `json:"total"`
+	Limit      int64  `json:"limit"`
+	Offset     int64  `json:"offset"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	// TotalEstimated is set when there were too many matches to count and
+	// Total is only the count limit
+	TotalEstimated bool `json:"totalEstimated,omitempty"`
 }
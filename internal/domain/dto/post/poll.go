@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// PollCreate attaches a poll to a new post. Results are shown to a voter only
+// after voting when HideResults is set, and to everyone once the poll closes.
+type PollCreate struct {
+	Options     []string   `json:"options" validate:"required,min=2,max=10,unique,dive,required,max=100"`
+	Multiple    bool       `json:"multiple"`
+	HideResults bool       `json:"hideResults"`
+	ClosesAt    *time.Time `json:"closesAt" validate:"omitempty"`
+}
+
+type PollVote struct {
+	OptionIds []string `json:"optionIds" validate:"required,min=1,unique,dive,required"`
+}
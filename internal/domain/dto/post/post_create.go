@@ -1,6 +1,20 @@
 package dto
 
+import "time"
+
 type PostCreate struct {
-	PostInHtml string   `json:"postInHtml" validate:"required,min=2,max=500"`
-	Tags       []string `json:"tags" validate:"required,dive,min=1"`
+	PostInHtml  string             `json:"postInHtml" validate:"required,min=2"`
+	Tags        []string           `json:"tags" validate:"required,dive,min=1"`
+	Visibility  string             `json:"visibility" validate:"omitempty,oneof=public friends friends-of-friends only-me"`
+	Attachments []AttachmentCreate `json:"attachments" validate:"omitempty,dive"`
+	Poll        *PollCreate        `json:"poll" validate:"omitempty"`
+	// PublishAt schedules the post instead of publishing it right away
+	PublishAt *time.Time `json:"publishAt" validate:"omitempty"`
+}
+
+// AttachmentCreate references an image returned by the image upload
+// endpoint. Attachments are shown in the order they are sent.
+type AttachmentCreate struct {
+	ImageUrl string `json:"imageUrl" validate:"required,url"`
+	AltText  string `json:"altText" validate:"max=300"`
 }
@@ -1,8 +1,22 @@
 package dto
 
+import "github.com/shafaalafghany/segokuning-social-app/internal/common/utils/rank"
+
 type PostFilter struct {
 	Limit     int64    `json:"limit" validate:"omitempty,numeric,min=0" schema:"limit"`
 	Offset    int64    `json:"offset" validate:"omitempty,numeric,min=0" schema:"offset"`
 	Search    string   `json:"search" validate:"omitempty,min=1" schema:"search"`
 	SearchTag []string `json:"searchTag" validate:"omitempty,min=0,dive,min=1" schema:"searchTag"`
+	Feed      string   `json:"feed" validate:"omitempty,eq=friends|eq=following|eq=mentions" schema:"feed"`
+	Cursor    string   `json:"cursor" validate:"omitempty" schema:"cursor"`
+	Sort      string   `json:"sort" validate:"omitempty,eq=recent|eq=ranked" schema:"sort"`
+	// Tag lists every visible post with the tag instead of the caller's feed
+	Tag string `json:"-" schema:"-"`
+	// CountLimit caps how many matches are counted for the total, 0 skips
+	// counting
+	CountLimit int64 `json:"-" schema:"-"`
+	// Ranking and RankCandidates tune the ranked feed: how posts are scored
+	// and how many of the newest posts are scored
+	Ranking        rank.Weights `json:"-" schema:"-"`
+	RankCandidates int64        `json:"-" schema:"-"`
 }
@@ -13,8 +13,20 @@ type PostData struct {
 }
 
 type Post struct {
-	ID       string               `json:"postId"`
-	Post     entity.Post          `json:"post"`
-	Comments []dtocomment.Comment `json:"comments"`
-	Creator  entity.User          `json:"creator"`
+	ID                string               `json:"postId"`
+	Post              entity.Post          `json:"post"`
+	Comments          []dtocomment.Comment `json:"comments"`
+	Creator           entity.User          `json:"creator"`
+	ReshareOf         *Reshare             `json:"reshareOf,omitempty"`
+	NextCommentCursor string               `json:"nextCommentCursor,omitempty"`
+}
+
+// Reshare is the original post embedded in a reshare. Originals that were
+// deleted or are hidden from the viewer are a tombstone with only ID and
+// Unavailable set.
+type Reshare struct {
+	ID          string       `json:"postId"`
+	Post        *entity.Post `json:"post,omitempty"`
+	Creator     *entity.User `json:"creator,omitempty"`
+	Unavailable bool         `json:"unavailable,omitempty"`
 }
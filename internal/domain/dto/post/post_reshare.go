@@ -0,0 +1,8 @@
+package dto
+
+// ReshareCreate shares a post; a reshare with PostInHtml is a quote.
+type ReshareCreate struct {
+	PostInHtml string   `json:"postInHtml" validate:"omitempty"`
+	Tags       []string `json:"tags" validate:"omitempty,dive,min=1"`
+	Visibility string   `json:"visibility" validate:"omitempty,oneof=public friends friends-of-friends only-me"`
+}
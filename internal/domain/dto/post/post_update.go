@@ -0,0 +1,8 @@
+package dto
+
+// PostUpdate carries the editable fields of a post and validates them the
+// same way as PostCreate.
+type PostUpdate struct {
+	PostInHtml string   `json:"postInHtml" validate:"required,min=2"`
+	Tags       []string `json:"tags" validate:"required,dive,min=1"`
+}
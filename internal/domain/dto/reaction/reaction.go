@@ -0,0 +1,11 @@
+package dto
+
+type ReactionToggle struct {
+	Type string `json:"type" validate:"required,oneof=like love haha wow sad angry"`
+}
+
+// ReactionResult is the caller's reaction after a toggle, empty when it was
+// removed.
+type ReactionResult struct {
+	Type string `json:"type"`
+}
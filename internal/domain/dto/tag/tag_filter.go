@@ -0,0 +1,17 @@
+package dto
+
+type TrendingFilter struct {
+	Window string `json:"window" validate:"omitempty" schema:"window"`
+	Limit  int64  `json:"limit" validate:"omitempty,numeric,min=0,max=50" schema:"limit"`
+}
+
+type TagPostFilter struct {
+	Limit  int64  `json:"limit" validate:"omitempty,numeric,min=0" schema:"limit"`
+	Offset int64  `json:"offset" validate:"omitempty,numeric,min=0" schema:"offset"`
+	Cursor string `json:"cursor" validate:"omitempty" schema:"cursor"`
+}
+
+type AutocompleteFilter struct {
+	Query string `json:"q" validate:"required" schema:"q"`
+	Limit int64  `json:"limit" validate:"omitempty,numeric,min=0,max=20" schema:"limit"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import (
+	dtopost "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+)
+
+// TagPage is a page of the posts with a tag. PostCount counts every post with
+// the tag the caller can see, not only the ones on the page.
+type TagPage struct {
+	Tag       string         `json:"tag"`
+	PostCount int64          `json:"postCount"`
+	Posts     []dtopost.Post `json:"posts"`
+}
@@ -7,4 +7,9 @@ type UserFilter struct {
 	SortBy     string `json:"sortBy" validate:"omitempty,eq=friendCount|eq=createdAt" schema:"sortBy"`
 	OrderBy    string `json:"orderBy" validate:"omitempty,eq=asc|eq=desc" schema:"orderBy"`
 	Search     string `json:"search" validate:"omitempty,min=1" schema:"search"`
+	ListId     string `json:"listId" validate:"omitempty" schema:"listId"`
+	Cursor     string `json:"cursor" validate:"omitempty" schema:"cursor"`
+	// CountLimit caps how many matches are counted for the total, 0 skips
+	// counting
+	CountLimit int64 `json:"-" schema:"-"`
 }
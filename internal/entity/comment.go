@@ -7,5 +7,6 @@ type Comment struct {
 	Comment   string    `json:"comment"`
 	PostId    string    `json:"-"`
 	UserId    string    `json:"-"`
+	Mentions  []Mention `json:"-"`
 	CreatedAt time.Time `json:"createdAt"`
 }
@@ -0,0 +1,28 @@
+package entity
+
+// Counter describes a denormalized count column and the query that
+// recomputes its true value from the source table. Source is a scalar
+// subquery that may reference the current row through Table. Key is the
+// unique column rows are checked in order of, id when empty.
+type Counter struct {
+	Name   string
+	Table  string
+	Key    string
+	Column string
+	Source string
+}
+
+// CounterBatch reports one checked batch. LastId is the Key of its last row.
+type CounterBatch struct {
+	LastId    string
+	Scanned   int64
+	Drifted   int64
+	Corrected int64
+}
+
+type CounterResult struct {
+	Counter   string `json:"counter"`
+	Scanned   int64  `json:"scanned"`
+	Drifted   int64  `json:"drifted"`
+	Corrected int64  `json:"corrected"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+// Draft is an unfinished post. Nothing is validated until it is published.
+// Version goes up with every save and has to be sent back with the next one,
+// so a stale copy can't overwrite a newer save.
+type Draft struct {
+	ID          string            `json:"draftId"`
+	UserId      string            `json:"-"`
+	PostInHtml  string            `json:"postInHtml"`
+	Tags        []string          `json:"tags"`
+	Visibility  string            `json:"visibility"`
+	Attachments []DraftAttachment `json:"attachments"`
+	Version     int64             `json:"version"`
+	CreatedAt   string            `json:"createdAt"`
+	UpdatedAt   string            `json:"updatedAt"`
+}
+
+// DraftAttachment is an image a draft refers to by url. It is only checked
+// against the user's uploads when the draft is published.
+type DraftAttachment struct {
+	ImageUrl string `json:"imageUrl"`
+	AltText  string `json:"altText"`
+}
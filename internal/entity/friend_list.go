@@ -0,0 +1,9 @@
+package entity
+
+type FriendList struct {
+	ID          string `json:"listId"`
+	UserId      string `json:"-"`
+	Name        string `json:"name"`
+	MemberCount int64  `json:"memberCount"`
+	CreatedAt   string `json:"createdAt"`
+}
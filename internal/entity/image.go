@@ -1,5 +1,7 @@
 package entity
 
 type Image struct {
+	ID       string `json:"-"`
+	UserId   string `json:"-"`
 	ImageUrl string `json:"imageUrl"`
 }
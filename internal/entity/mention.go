@@ -0,0 +1,11 @@
+package entity
+
+const (
+	MentionTargetPost    = "post"
+	MentionTargetComment = "comment"
+)
+
+type Mention struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "errors"
+
+// ErrPollClosed is returned for a vote that arrives after the poll closed.
+var ErrPollClosed = errors.New("poll is closed")
+
+// Poll is a question attached to a post. While the results are hidden from
+// the viewer, the vote counts are left out.
+type Poll struct {
+	Options       []PollOption `json:"options"`
+	Multiple      bool         `json:"multiple"`
+	HideResults   bool         `json:"hideResults"`
+	ClosesAt      string       `json:"closesAt,omitempty"`
+	Closed        bool         `json:"closed"`
+	ResultsHidden bool         `json:"resultsHidden"`
+	TotalVoters   *int64       `json:"totalVoters,omitempty"`
+	// MyVotes are the option ids the viewer voted for
+	MyVotes []string `json:"myVotes"`
+}
+
+type PollOption struct {
+	ID    string `json:"optionId"`
+	Label string `json:"label"`
+	Votes *int64 `json:"votes,omitempty"`
+}
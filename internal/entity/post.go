@@ -1,9 +1,41 @@
 package entity
 
 type Post struct {
-	ID         string   `json:"-"`
-	UserId     string   `json:"-"`
+	ID           string       `json:"-"`
+	UserId       string       `json:"-"`
+	PostInHtml   string       `json:"postInHtml"`
+	Tags         []string     `json:"tags"`
+	Visibility   string       `json:"visibility"`
+	Attachments  []Attachment `json:"attachments"`
+	Reactions    Reactions    `json:"reactions"`
+	Mentions     []Mention    `json:"mentions"`
+	Poll         *Poll        `json:"poll,omitempty"`
+	ReshareOf    string       `json:"-"`
+	ReshareCount int64        `json:"reshareCount"`
+	Edited       bool         `json:"edited"`
+	EditedAt     string       `json:"editedAt,omitempty"`
+	CreatedAt    string       `json:"createdAt"`
+}
+
+// Attachment is an uploaded image shown with a post, ordered by Position.
+type Attachment struct {
+	ImageId  string `json:"-"`
+	ImageUrl string `json:"imageUrl"`
+	AltText  string `json:"altText"`
+	Position int    `json:"position"`
+}
+
+type PostRevision struct {
+	ID         string   `json:"revisionId"`
+	PostId     string   `json:"-"`
 	PostInHtml string   `json:"postInHtml"`
 	Tags       []string `json:"tags"`
 	CreatedAt  string   `json:"createdAt"`
 }
+
+const (
+	VisibilityPublic           = "public"
+	VisibilityFriends          = "friends"
+	VisibilityFriendsOfFriends = "friends-of-friends"
+	VisibilityOnlyMe           = "only-me"
+)
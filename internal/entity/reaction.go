@@ -0,0 +1,32 @@
+package entity
+
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetComment = "comment"
+)
+
+// ReactionTypes is the fixed set of reactions a user can leave.
+var ReactionTypes = []string{"like", "love", "haha", "wow", "sad", "angry"}
+
+type Reaction struct {
+	TargetType string
+	TargetId   string
+	UserId     string
+	Type       string
+}
+
+// Reactions summarizes the reactions on a post or comment. Counts holds every
+// reaction type and Mine the caller's own reaction, if any.
+type Reactions struct {
+	Counts map[string]int64 `json:"counts"`
+	Mine   string           `json:"mine,omitempty"`
+}
+
+func NewReactions() Reactions {
+	counts := make(map[string]int64, len(ReactionTypes))
+	for _, reactionType := range ReactionTypes {
+		counts[reactionType] = 0
+	}
+
+	return Reactions{Counts: counts}
+}
@@ -0,0 +1,17 @@
+package entity
+
+// ScheduledPost is a post waiting to be published at PublishAt. It becomes a
+// post with the same id once published. Attempts and LastError tell how often
+// publishing it failed and why.
+type ScheduledPost struct {
+	ID          string       `json:"scheduledPostId"`
+	UserId      string       `json:"-"`
+	PostInHtml  string       `json:"postInHtml"`
+	Tags        []string     `json:"tags"`
+	Visibility  string       `json:"visibility"`
+	Attachments []Attachment `json:"attachments"`
+	PublishAt   string       `json:"publishAt"`
+	Attempts    int          `json:"attempts"`
+	LastError   string       `json:"lastError,omitempty"`
+	CreatedAt   string       `json:"createdAt"`
+}
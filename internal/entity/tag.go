@@ -0,0 +1,18 @@
+package entity
+
+// TrendingTag is a tag with its post counts in the requested window and in
+// the window right before it.
+type TrendingTag struct {
+	Tag           string  `json:"tag"`
+	PostCount     int64   `json:"postCount"`
+	PreviousCount int64   `json:"previousCount"`
+	Score         float64 `json:"score"`
+}
+
+// TagUsage is a known tag with the number of public posts using it.
+// RecentlyUsed is set when the caller used the tag recently.
+type TagUsage struct {
+	Tag          string `json:"tag"`
+	UsageCount   int64  `json:"usageCount"`
+	RecentlyUsed bool   `json:"recentlyUsed"`
+}
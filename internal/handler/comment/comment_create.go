@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/mention"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
 	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/comment"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
@@ -54,10 +55,9 @@ func (uh *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request)
 	userId = ctx.Value("user_id").(string)
 	commentId := uuid.NewString()
 
-	post, err := uh.pr.FindById(ctx, data.PostId)
-	if err != nil {
+	if _, err := uh.pr.FindById(ctx, data.PostId); err != nil {
 		if err == pgx.ErrNoRows {
-			uh.log.Info("user is not found", zap.Error(err))
+			uh.log.Info("post is not found", zap.Error(err))
 			(&response.Response{
 				HttpStatus: http.StatusNotFound,
 				Message:    "Post not found",
@@ -65,7 +65,7 @@ func (uh *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		uh.log.Info("failed to get user", zap.Error(err))
+		uh.log.Info("failed to get post", zap.Error(err))
 		(&response.Response{
 			HttpStatus: http.StatusInternalServerError,
 			Message:    err.Error(),
@@ -73,25 +73,23 @@ func (uh *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if userId != post.UserId {
-		count, err := uh.fr.FindByRelation(ctx, userId, post.UserId)
-		if err != nil {
-			uh.log.Info("failed to get user relation", zap.Error(err))
-			(&response.Response{
-				HttpStatus: http.StatusInternalServerError,
-				Message:    err.Error(),
-			}).GenerateResponse(w)
-			return
-		}
+	visible, err := uh.pr.IsVisible(ctx, data.PostId, userId)
+	if err != nil {
+		uh.log.Info("failed to check post visibility", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
 
-		if count <= 0 {
-			uh.log.Info("you cannot commented because you are not friend with this user")
-			(&response.Response{
-				HttpStatus: http.StatusBadRequest,
-				Message:    "You cannot commented because you are not friend with this user",
-			}).GenerateResponse(w)
-			return
-		}
+	if !visible {
+		uh.log.Info("you cannot comment on this post")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "You cannot comment on this post",
+		}).GenerateResponse(w)
+		return
 	}
 
 	commentEntity := entity.Comment{
@@ -100,6 +98,9 @@ func (uh *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request)
 		UserId:  userId,
 		PostId:  data.PostId,
 	}
+	for _, mentionedId := range mention.Extract(data.Comment) {
+		commentEntity.Mentions = append(commentEntity.Mentions, entity.Mention{UserId: mentionedId})
+	}
 
 	if err := uh.cr.Insert(ctx, commentEntity); err != nil {
 		uh.log.Info("failed to insert data", zap.Error(err))
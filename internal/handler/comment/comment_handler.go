@@ -10,9 +10,9 @@ import (
 )
 
 type CommentHandler struct {
-	fr  interfaces.FriendRepository
 	cr  interfaces.CommentRepository
 	pr  interfaces.PostRepository
+	rr  interfaces.ReactionRepository
 	val *validator.Validate
 	cfg config.Configuration
 	log *zap.Logger
@@ -20,17 +20,17 @@ type CommentHandler struct {
 
 func NewCommentHandler(
 	r chi.Router,
-	fr interfaces.FriendRepository,
 	cr interfaces.CommentRepository,
 	pr interfaces.PostRepository,
+	rr interfaces.ReactionRepository,
 	val *validator.Validate,
 	cfg config.Configuration,
 	log *zap.Logger,
 ) {
 	fh := &CommentHandler{
-		fr:  fr,
 		cr:  cr,
 		pr:  pr,
+		rr:  rr,
 		val: val,
 		cfg: cfg,
 		log: log,
@@ -39,5 +39,6 @@ func NewCommentHandler(
 	r.Route("/post/comment", func(r chi.Router) {
 		r.Use(jwt.JwtMiddleware)
 		r.Post("/", fh.CreateComment)
+		r.Post("/{commentId}/reaction", fh.ReactComment)
 	})
 }
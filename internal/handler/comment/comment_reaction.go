@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/reaction"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (uh *CommentHandler) ReactComment(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		data   dto.ReactionToggle
+	)
+
+	commentId := chi.URLParam(r, "commentId")
+	if err := validation.UuidValidation(commentId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+
+	comment, err := uh.cr.FindById(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("comment is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Comment not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get comment", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	// comments share the visibility of their post
+	postId := comment.PostId
+	if _, err := uh.pr.FindById(ctx, postId); err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("post is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	visible, err := uh.pr.IsVisible(ctx, postId, userId)
+	if err != nil {
+		uh.log.Info("failed to check post visibility", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !visible {
+		uh.log.Info("you cannot react to this comment")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "You cannot react to this comment",
+		}).GenerateResponse(w)
+		return
+	}
+
+	reaction, err := uh.rr.Toggle(ctx, entity.Reaction{
+		TargetType: entity.ReactionTargetComment,
+		TargetId:   commentId,
+		UserId:     userId,
+		Type:       data.Type,
+	})
+	if err != nil {
+		uh.log.Info("failed to toggle reaction", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Toggle reaction success",
+		Data:       dto.ReactionResult{Type: reaction},
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/follow"
+	"go.uber.org/zap"
+)
+
+func (fh *FollowHandler) CreateFollow(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		data   dto.FollowData
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		fh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := fh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			fh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+	followingId := data.UserId
+
+	if userId == followingId {
+		fh.log.Info("cannot follow self")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Cannot follow self",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := validation.UuidValidation(followingId); err != nil {
+		fh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if _, err := fh.ur.FindById(ctx, followingId); err != nil {
+		if err == pgx.ErrNoRows {
+			fh.log.Info("user is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "User not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		fh.log.Info("failed to get user", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	count, err := fh.fr.FindByRelation(ctx, userId, followingId)
+	if err != nil {
+		fh.log.Info("failed to get follow relation", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if count > 0 {
+		fh.log.Info("you already follow this user")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "You are already following this user",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := fh.fr.Insert(ctx, userId, followingId); err != nil {
+		fh.log.Info("failed to follow user", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Follow user success",
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/follow"
+	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+type followLister func(context.Context, string, dto.FollowFilter) ([]entity.User, int64, error)
+
+func (fh *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	fh.getFollows(w, r, fh.fr.GetFollowers)
+}
+
+func (fh *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	fh.getFollows(w, r, fh.fr.GetFollowing)
+}
+
+func (fh *FollowHandler) getFollows(w http.ResponseWriter, r *http.Request, list followLister) {
+	var (
+		filter dto.FollowFilter
+	)
+
+	if err := r.ParseForm(); err != nil {
+		fh.log.Info("failed to parse form", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := validation.ValidateParams(r, filter); err != nil {
+		fh.log.Info("failed to validate params", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := schema.NewDecoder().Decode(&filter, r.Form); err != nil {
+		fh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := fh.val.Struct(filter); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			fh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+	if filter.UserId != "" {
+		if err := validation.UuidValidation(filter.UserId); err != nil {
+			fh.log.Info("failed to validate uuid", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    err.Error(),
+			}).GenerateResponse(w)
+			return
+		}
+
+		if _, err := fh.ur.FindById(ctx, filter.UserId); err != nil {
+			if err == pgx.ErrNoRows {
+				fh.log.Info("user is not found", zap.Error(err))
+				(&response.Response{
+					HttpStatus: http.StatusNotFound,
+					Message:    "User not found",
+				}).GenerateResponse(w)
+				return
+			}
+
+			fh.log.Info("failed to get user", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusInternalServerError,
+				Message:    err.Error(),
+			}).GenerateResponse(w)
+			return
+		}
+		userId = filter.UserId
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 5
+	}
+	filter.Offset = filter.Limit * filter.Offset
+
+	data, count, err := list(ctx, userId, filter)
+	if err != nil {
+		fh.log.Info("failed to get follow list", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.ResponseWithMeta{
+		HttpStatus: http.StatusOK,
+		Data:       data,
+		Meta: metadto.Meta{
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+			Total:  count,
+		},
+	}).GenerateResponseMeta(w)
+}
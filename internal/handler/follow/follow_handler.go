@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shafaalafghany/segokuning-social-app/config"
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/jwt"
+	"go.uber.org/zap"
+)
+
+type FollowHandler struct {
+	ur  interfaces.UserRepository
+	fr  interfaces.FollowRepository
+	val *validator.Validate
+	cfg config.Configuration
+	log *zap.Logger
+}
+
+func NewFollowHandler(
+	r chi.Router,
+	ur interfaces.UserRepository,
+	fr interfaces.FollowRepository,
+	val *validator.Validate,
+	cfg config.Configuration,
+	log *zap.Logger,
+) {
+	fh := &FollowHandler{
+		ur:  ur,
+		fr:  fr,
+		val: val,
+		cfg: cfg,
+		log: log,
+	}
+
+	r.Route("/follow", func(r chi.Router) {
+		r.Use(jwt.JwtMiddleware)
+		r.Post("/", fh.CreateFollow)
+		r.Delete("/", fh.DeleteFollow)
+		r.Get("/follower", fh.GetFollowers)
+		r.Get("/following", fh.GetFollowing)
+	})
+}
@@ -1,13 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/schema"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
-	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
 	userdto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/user"
 	"go.uber.org/zap"
 )
@@ -60,12 +61,28 @@ func (uh *FriendHandler) GetFriend(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userId = ctx.Value("user_id").(string)
 
+	if filter.ListId != "" {
+		if _, ok := uh.ownedList(ctx, w, filter.ListId, userId); !ok {
+			return
+		}
+	}
+
 	if filter.Limit == 0 {
 		filter.Limit = 5
 	}
 	filter.Offset = filter.Limit * filter.Offset
-	data, count, err := uh.ur.GetUserWithFilter(ctx, userId, filter)
+	filter.CountLimit = int64(uh.cfg.List.CountLimit)
+	data, meta, err := uh.ur.GetUserWithFilter(ctx, userId, filter)
 	if err != nil {
+		if errors.Is(err, cursor.ErrInvalid) {
+			uh.log.Info("failed to decode cursor", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    err.Error(),
+			}).GenerateResponse(w)
+			return
+		}
+
 		uh.log.Info("failed to get user with filter", zap.Error(err))
 		(&response.Response{
 			HttpStatus: http.StatusInternalServerError,
@@ -77,10 +94,6 @@ func (uh *FriendHandler) GetFriend(w http.ResponseWriter, r *http.Request) {
 	(&response.ResponseWithMeta{
 		HttpStatus: http.StatusOK,
 		Data:       data,
-		Meta: metadto.Meta{
-			Limit:  filter.Limit,
-			Offset: filter.Offset,
-			Total:  count,
-		},
+		Meta:       meta,
 	}).GenerateResponseMeta(w)
 }
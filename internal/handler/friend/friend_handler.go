@@ -12,6 +12,8 @@ import (
 type FriendHandler struct {
 	ur  interfaces.UserRepository
 	fr  interfaces.FriendRepository
+	sr  interfaces.SuggestionRepository
+	lr  interfaces.FriendListRepository
 	val *validator.Validate
 	cfg config.Configuration
 	log *zap.Logger
@@ -21,6 +23,8 @@ func NewFriendHandler(
 	r chi.Router,
 	ur interfaces.UserRepository,
 	fr interfaces.FriendRepository,
+	sr interfaces.SuggestionRepository,
+	lr interfaces.FriendListRepository,
 	val *validator.Validate,
 	cfg config.Configuration,
 	log *zap.Logger,
@@ -28,6 +32,8 @@ func NewFriendHandler(
 	fh := &FriendHandler{
 		ur:  ur,
 		fr:  fr,
+		sr:  sr,
+		lr:  lr,
 		val: val,
 		cfg: cfg,
 		log: log,
@@ -38,5 +44,15 @@ func NewFriendHandler(
 		r.Get("/", fh.GetFriend)
 		r.Post("/", fh.CreateFriend)
 		r.Delete("/", fh.DeleteFriend)
+		r.Get("/mutual", fh.GetMutualFriend)
+		r.Get("/suggestion", fh.GetSuggestion)
+
+		r.Route("/list", func(r chi.Router) {
+			r.Get("/", fh.GetFriendList)
+			r.Post("/", fh.CreateFriendList)
+			r.Delete("/{listId}", fh.DeleteFriendList)
+			r.Post("/{listId}/member", fh.AddFriendListMember)
+			r.Delete("/{listId}/member", fh.RemoveFriendListMember)
+		})
 	})
 }
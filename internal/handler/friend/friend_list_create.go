@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/friend"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (uh *FriendHandler) CreateFriendList(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		data   dto.FriendListCreate
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+
+	count, err := uh.lr.NameCheck(ctx, userId, data.Name)
+	if err != nil {
+		uh.log.Info("failed to check friend list name", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if count > 0 {
+		uh.log.Info("friend list name already exists")
+		(&response.Response{
+			HttpStatus: http.StatusConflict,
+			Message:    "Friend list with this name already exists",
+		}).GenerateResponse(w)
+		return
+	}
+
+	list := entity.FriendList{
+		ID:     uuid.NewString(),
+		UserId: userId,
+		Name:   data.Name,
+	}
+
+	if err := uh.lr.Insert(ctx, list); err != nil {
+		uh.log.Info("failed to insert data", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Add friend list success",
+		Data:       list,
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"go.uber.org/zap"
+)
+
+func (uh *FriendHandler) DeleteFriendList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	list, ok := uh.ownedList(ctx, w, chi.URLParam(r, "listId"), userId)
+	if !ok {
+		return
+	}
+
+	if err := uh.lr.Delete(ctx, list.ID); err != nil {
+		uh.log.Info("failed to delete friend list", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Delete friend list success",
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (uh *FriendHandler) GetFriendList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	data, err := uh.lr.GetByUser(ctx, userId)
+	if err != nil {
+		uh.log.Info("failed to get friend lists", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Get friend lists success",
+		Data:       data,
+	}).GenerateResponse(w)
+}
+
+// ownedList loads a friend list and makes sure it belongs to userId. Lists
+// of other users are reported as not found. When ok is false the response
+// has already been written.
+func (uh *FriendHandler) ownedList(ctx context.Context, w http.ResponseWriter, listId, userId string) (list entity.FriendList, ok bool) {
+	if err := validation.UuidValidation(listId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return list, false
+	}
+
+	list, err := uh.lr.FindById(ctx, listId)
+	if err != nil && err != pgx.ErrNoRows {
+		uh.log.Info("failed to get friend list", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return list, false
+	}
+
+	if err == pgx.ErrNoRows || list.UserId != userId {
+		uh.log.Info("friend list is not found")
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Friend list not found",
+		}).GenerateResponse(w)
+		return list, false
+	}
+
+	return list, true
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/friend"
+	"go.uber.org/zap"
+)
+
+func (uh *FriendHandler) AddFriendListMember(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		data   dto.FriendData
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	if err := validation.UuidValidation(data.UserId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+
+	list, ok := uh.ownedList(ctx, w, chi.URLParam(r, "listId"), userId)
+	if !ok {
+		return
+	}
+
+	count, err := uh.fr.FindByRelation(ctx, userId, data.UserId)
+	if err != nil {
+		uh.log.Info("failed to get user relation", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if count <= 0 {
+		uh.log.Info("you are not friend with this user")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "You are not friend with this user",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.lr.InsertMember(ctx, list.ID, data.UserId); err != nil {
+		uh.log.Info("failed to add friend list member", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Add friend list member success",
+	}).GenerateResponse(w)
+}
+
+func (uh *FriendHandler) RemoveFriendListMember(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		data   dto.FriendData
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	if err := validation.UuidValidation(data.UserId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+
+	list, ok := uh.ownedList(ctx, w, chi.URLParam(r, "listId"), userId)
+	if !ok {
+		return
+	}
+
+	if err := uh.lr.DeleteMember(ctx, list.ID, data.UserId); err != nil {
+		uh.log.Info("failed to remove friend list member", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Remove friend list member success",
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/friend"
+	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
+	"go.uber.org/zap"
+)
+
+func (uh *FriendHandler) GetMutualFriend(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		filter dto.MutualFriendFilter
+	)
+
+	if err := r.ParseForm(); err != nil {
+		uh.log.Info("failed to parse form", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := validation.ValidateParams(r, filter); err != nil {
+		uh.log.Info("failed to validate params", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := schema.NewDecoder().Decode(&filter, r.Form); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(filter); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	if err := validation.UuidValidation(filter.UserId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+
+	if userId == filter.UserId {
+		uh.log.Info("cannot get mutual friends with self")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Cannot get mutual friends with self",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if _, err := uh.ur.FindById(ctx, filter.UserId); err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("user is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "User not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get user", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 5
+	}
+	filter.Offset = filter.Limit * filter.Offset
+
+	data, total, err := uh.fr.GetMutualFriends(ctx, userId, filter)
+	if err != nil {
+		uh.log.Info("failed to get mutual friends", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.ResponseWithMeta{
+		HttpStatus: http.StatusOK,
+		Data:       data,
+		Meta: metadto.Meta{
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+			Total:  total,
+		},
+	}).GenerateResponseMeta(w)
+}
@@ -4,18 +4,23 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/shafaalafghany/segokuning-social-app/config"
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
 	"github.com/shafaalafghany/segokuning-social-app/pkg/jwt"
 	"go.uber.org/zap"
 )
 
 type ImageHandler struct {
+	ir  interfaces.ImageRepository
+	st  interfaces.Storage
 	val *validator.Validate
 	cfg config.Configuration
 	log *zap.Logger
 }
 
-func NewImageHandler(r chi.Router, val validator.Validate, cfg config.Configuration, log *zap.Logger) {
+func NewImageHandler(r chi.Router, ir interfaces.ImageRepository, st interfaces.Storage, val validator.Validate, cfg config.Configuration, log *zap.Logger) {
 	ih := &ImageHandler{
+		ir:  ir,
+		st:  st,
 		val: &val,
 		cfg: cfg,
 		log: log,
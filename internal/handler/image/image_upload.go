@@ -1,17 +1,9 @@
 package image
 
 import (
-	"crypto/rand"
-	"encoding/hex"
-	"fmt"
-	"mime/multipart"
 	"net/http"
-	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
@@ -50,7 +42,7 @@ func (im *ImageHandler) Store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	imageUrl, err := im.UploadImageToS3(fileHeader.Filename, file)
+	imageUrl, err := im.st.Upload(fileHeader.Filename, file)
 	if err != nil {
 		im.log.Info("failed to upload image to s3", zap.Error(err))
 		(&response.Response{
@@ -60,59 +52,26 @@ func (im *ImageHandler) Store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx := r.Context()
 	data := &entity.Image{
+		ID:       uuid.NewString(),
+		UserId:   ctx.Value("user_id").(string),
 		ImageUrl: imageUrl,
 	}
 
+	// record the upload so posts can only attach images their author uploaded
+	if err := im.ir.Insert(ctx, *data); err != nil {
+		im.log.Info("failed to insert image", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
 	(&response.Response{
 		HttpStatus: http.StatusOK,
 		Message:    "File uploaded sucessfully",
 		Data:       data,
 	}).GenerateResponse(w)
 }
-
-func (im *ImageHandler) UploadImageToS3(fileName string, image multipart.File) (string, error) {
-	bucketName := im.cfg.S3.BucketName
-	s3Id := im.cfg.S3.ID
-	s3SecretKey := im.cfg.S3.SecretKey
-	s3Region := im.cfg.S3.Region
-
-	ses, err := session.NewSession(&aws.Config{
-		Region: aws.String(s3Region),
-		Credentials: credentials.NewStaticCredentials(
-			s3Id,
-			s3SecretKey,
-			"",
-		),
-	})
-	if err != nil {
-		im.log.Info("failed to create new session aws", zap.Error(err))
-		return "", err
-	}
-
-	fileName = generateRandomString(10) + time.Now().Format("20060102150405") + "-" + fileName
-
-	svc := s3.New(ses)
-	_, err = svc.PutObject(&s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(fileName),
-		Body:   image,
-		ACL:    aws.String("public-read"),
-	})
-	if err != nil {
-		im.log.Info("failed to put object", zap.Error(err))
-		return "", err
-	}
-
-	imageUrl := fmt.Sprintf("https://%s.s3-%s.amazonaws.com/%s", bucketName, "ap-southeast-1", fileName)
-
-	return imageUrl, nil
-}
-
-func generateRandomString(length int) string {
-	bytes := make([]byte, length)
-	if _, err := rand.Read(bytes); err != nil {
-		return ""
-	}
-	return hex.EncodeToString(bytes)
-}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	commentdto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/comment"
+	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) GetPostComment(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter commentdto.CommentFilter
+	)
+
+	postId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(postId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		uh.log.Info("failed to parse form", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := validation.ValidateParams(r, filter); err != nil {
+		uh.log.Info("failed to validate params", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := schema.NewDecoder().Decode(&filter, r.Form); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(filter); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	if filter.Cursor != "" {
+		if _, _, err := cursor.DecodeTime(filter.Cursor); err != nil {
+			uh.log.Info("failed to decode cursor", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    err.Error(),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId, _ := ctx.Value("user_id").(string)
+
+	visible, err := uh.pr.IsVisible(ctx, postId, userId)
+	if err != nil {
+		uh.log.Info("failed to check post visibility", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !visible {
+		uh.log.Info("post is not found")
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Post not found",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+
+	data, next, err := uh.cr.GetByPost(ctx, postId, userId, filter)
+	if err != nil {
+		uh.log.Info("failed to get post comments", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	total, err := uh.cr.CountByPost(ctx, postId)
+	if err != nil {
+		uh.log.Info("failed to count post comments", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.ResponseWithMeta{
+		HttpStatus: http.StatusOK,
+		Data:       data,
+		Meta: metadto.Meta{
+			Limit:      filter.Limit,
+			Total:      total,
+			NextCursor: next,
+		},
+	}).GenerateResponseMeta(w)
+}
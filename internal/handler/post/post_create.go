@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/mention"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/tag"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
 	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
@@ -42,15 +48,18 @@ func (uh *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 	userId = ctx.Value("user_id").(string)
-	postId := uuid.NewString()
 
-	postEntity := entity.Post{
-		ID:         postId,
-		PostInHtml: data.PostInHtml,
-		Tags:       data.Tags,
+	postEntity, ok := uh.newPost(ctx, w, userId, data)
+	if !ok {
+		return
+	}
+
+	if data.PublishAt != nil {
+		uh.schedulePost(ctx, w, userId, postEntity, *data.PublishAt)
+		return
 	}
 
-	if err := uh.pr.Insert(ctx, postEntity, userId); err != nil {
+	if err := uh.pr.Insert(ctx, postEntity, userId, uh.cfg.Post.FanoutLimit); err != nil {
 		uh.log.Info("failed to insert data", zap.Error(err))
 		(&response.Response{
 			HttpStatus: http.StatusInternalServerError,
@@ -65,3 +74,146 @@ func (uh *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	}).GenerateResponse(w)
 
 }
+
+// newPost checks and cleans up a post about to be created or scheduled: tags
+// are normalized, the html sanitized and attachments resolved to the caller's
+// uploads. Failures are written to w and reported with ok false.
+func (uh *PostHandler) newPost(ctx context.Context, w http.ResponseWriter, userId string, data dto.PostCreate) (post entity.Post, ok bool) {
+	tags, err := tag.NormalizeAll(data.Tags)
+	if err != nil {
+		uh.log.Info("failed to normalize tags", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return entity.Post{}, false
+	}
+	data.Tags = tags
+
+	// the sanitized html is what gets stored and served back to readers
+	data.PostInHtml = uh.san.Sanitize(data.PostInHtml)
+	if strings.TrimSpace(data.PostInHtml) == "" {
+		uh.log.Info("post has no allowed content")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "postInHtml has no allowed content",
+		}).GenerateResponse(w)
+		return entity.Post{}, false
+	}
+	if uh.tooLong(w, data.PostInHtml) {
+		return entity.Post{}, false
+	}
+
+	if data.Visibility == "" {
+		data.Visibility = entity.VisibilityFriends
+	}
+
+	if len(data.Attachments) > uh.cfg.Post.MaxAttachments {
+		uh.log.Info("too many attachments", zap.Int("count", len(data.Attachments)))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    fmt.Sprintf("A post can have at most %d attachments", uh.cfg.Post.MaxAttachments),
+		}).GenerateResponse(w)
+		return entity.Post{}, false
+	}
+
+	attachments, err := uh.attachments(ctx, userId, data.Attachments)
+	if err != nil {
+		uh.log.Info("failed to get attachments", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return entity.Post{}, false
+	}
+
+	if len(attachments) != len(data.Attachments) {
+		uh.log.Info("attachment is not uploaded by user")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Attachments must be images uploaded by you",
+		}).GenerateResponse(w)
+		return entity.Post{}, false
+	}
+
+	var poll *entity.Poll
+	if data.Poll != nil {
+		if data.Poll.ClosesAt != nil && !data.Poll.ClosesAt.After(time.Now()) {
+			uh.log.Info("poll close time is not in the future")
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    "poll closesAt must be in the future",
+			}).GenerateResponse(w)
+			return entity.Post{}, false
+		}
+
+		poll = &entity.Poll{
+			Multiple:    data.Poll.Multiple,
+			HideResults: data.Poll.HideResults,
+			Options:     make([]entity.PollOption, 0, len(data.Poll.Options)),
+		}
+		if data.Poll.ClosesAt != nil {
+			poll.ClosesAt = data.Poll.ClosesAt.UTC().Format(time.RFC3339)
+		}
+		for _, label := range data.Poll.Options {
+			poll.Options = append(poll.Options, entity.PollOption{ID: uuid.NewString(), Label: label})
+		}
+	}
+
+	return entity.Post{
+		ID:          uuid.NewString(),
+		PostInHtml:  data.PostInHtml,
+		Tags:        data.Tags,
+		Visibility:  data.Visibility,
+		Attachments: attachments,
+		Mentions:    mentions(mention.ExtractHtml(data.PostInHtml)),
+		Poll:        poll,
+	}, true
+}
+
+// attachments resolves the requested attachments to images uploaded by
+// userId. Urls not uploaded by userId are left out, so the caller can compare
+// lengths to reject them.
+func (uh *PostHandler) attachments(ctx context.Context, userId string, data []dto.AttachmentCreate) ([]entity.Attachment, error) {
+	attachments := make([]entity.Attachment, 0, len(data))
+	if len(data) == 0 {
+		return attachments, nil
+	}
+
+	urls := make([]string, 0, len(data))
+	for _, attachment := range data {
+		urls = append(urls, attachment.ImageUrl)
+	}
+
+	imageIds, err := uh.ir.FindByUrls(ctx, userId, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, attachment := range data {
+		imageId, ok := imageIds[attachment.ImageUrl]
+		if !ok {
+			continue
+		}
+
+		attachments = append(attachments, entity.Attachment{
+			ImageId:  imageId,
+			ImageUrl: attachment.ImageUrl,
+			AltText:  attachment.AltText,
+			Position: i,
+		})
+	}
+
+	return attachments, nil
+}
+
+// mentions turns the user ids mentioned in a post or comment into mention
+// records; the repository drops users that cannot see the post.
+func mentions(userIds []string) []entity.Mention {
+	data := make([]entity.Mention, 0, len(userIds))
+	for _, userId := range userIds {
+		data = append(data, entity.Mention{UserId: userId})
+	}
+
+	return data
+}
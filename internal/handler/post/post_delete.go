@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
+	postId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(postId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	post, err := uh.pr.FindById(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("post is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if post.UserId != userId {
+		uh.log.Info("only the author can delete this post")
+		(&response.Response{
+			HttpStatus: http.StatusForbidden,
+			Message:    "Only the author can delete this post",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.pr.Delete(ctx, postId); err != nil {
+		uh.log.Info("failed to delete post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Delete post success",
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/draft"
+	dtopost "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	data, err := uh.dr.GetByUser(ctx, userId)
+	if err != nil {
+		uh.log.Info("failed to get drafts", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Data:       data,
+	}).GenerateResponse(w)
+}
+
+func (uh *PostHandler) CreateDraft(w http.ResponseWriter, r *http.Request) {
+	data, ok := uh.decodeDraft(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	draft, err := uh.dr.Insert(ctx, newDraft(uuid.NewString(), userId, data))
+	if err != nil {
+		uh.log.Info("failed to insert draft", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Add draft success",
+		Data:       draft,
+	}).GenerateResponse(w)
+}
+
+// UpdateDraft saves over a draft. The request has to carry the version it
+// was based on; a draft saved elsewhere in the meantime is not overwritten.
+func (uh *PostHandler) UpdateDraft(w http.ResponseWriter, r *http.Request) {
+	data, ok := uh.decodeDraft(w, r)
+	if !ok {
+		return
+	}
+
+	if data.Version == 0 {
+		uh.log.Info("draft version is missing")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "version is required",
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	current, ok := uh.ownedDraft(ctx, w, chi.URLParam(r, "draftId"), userId)
+	if !ok {
+		return
+	}
+
+	draft := newDraft(current.ID, userId, data)
+	draft.Version = data.Version
+	draft, err := uh.dr.Update(ctx, draft)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("draft version is stale", zap.Int64("version", data.Version))
+			(&response.Response{
+				HttpStatus: http.StatusConflict,
+				Message:    "Draft was changed since this version, reload it and try again",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to update draft", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Update draft success",
+		Data:       draft,
+	}).GenerateResponse(w)
+}
+
+// PublishDraft turns a draft into a post. The draft goes through the same
+// checks as a new post and is removed in the same transaction the post is
+// created in, so it can only be published once.
+func (uh *PostHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	var (
+		data dto.DraftPublish
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	draft, ok := uh.ownedDraft(ctx, w, chi.URLParam(r, "draftId"), userId)
+	if !ok {
+		return
+	}
+
+	if draft.Version != data.Version {
+		uh.log.Info("draft version is stale", zap.Int64("version", data.Version))
+		(&response.Response{
+			HttpStatus: http.StatusConflict,
+			Message:    "Draft was changed since this version, reload it and try again",
+		}).GenerateResponse(w)
+		return
+	}
+
+	postData := dtopost.PostCreate{
+		PostInHtml:  draft.PostInHtml,
+		Tags:        draft.Tags,
+		Visibility:  draft.Visibility,
+		Attachments: make([]dtopost.AttachmentCreate, 0, len(draft.Attachments)),
+	}
+	for _, attachment := range draft.Attachments {
+		postData.Attachments = append(postData.Attachments, dtopost.AttachmentCreate{
+			ImageUrl: attachment.ImageUrl,
+			AltText:  attachment.AltText,
+		})
+	}
+
+	if err := uh.val.Struct(postData); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	post, ok := uh.newPost(ctx, w, userId, postData)
+	if !ok {
+		return
+	}
+
+	err := uh.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uh.pr.Insert(ctx, post, userId, uh.cfg.Post.FanoutLimit); err != nil {
+			return err
+		}
+
+		return uh.dr.Delete(ctx, draft.ID, draft.Version)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("draft changed while publishing", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusConflict,
+				Message:    "Draft was changed since this version, reload it and try again",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to publish draft", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Publish draft success",
+	}).GenerateResponse(w)
+}
+
+func (uh *PostHandler) decodeDraft(w http.ResponseWriter, r *http.Request) (data dto.DraftSave, ok bool) {
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return data, false
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return data, false
+		}
+	}
+
+	return data, true
+}
+
+// ownedDraft loads a draft of userId. Drafts of other users are reported as
+// not found.
+func (uh *PostHandler) ownedDraft(ctx context.Context, w http.ResponseWriter, id, userId string) (draft entity.Draft, ok bool) {
+	if err := validation.UuidValidation(id); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return draft, false
+	}
+
+	draft, err := uh.dr.FindById(ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		uh.log.Info("failed to get draft", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return draft, false
+	}
+
+	if err == pgx.ErrNoRows || draft.UserId != userId {
+		uh.log.Info("draft is not found")
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Draft not found",
+		}).GenerateResponse(w)
+		return draft, false
+	}
+
+	return draft, true
+}
+
+func newDraft(id, userId string, data dto.DraftSave) entity.Draft {
+	draft := entity.Draft{
+		ID:          id,
+		UserId:      userId,
+		PostInHtml:  data.PostInHtml,
+		Tags:        data.Tags,
+		Visibility:  data.Visibility,
+		Attachments: make([]entity.DraftAttachment, 0, len(data.Attachments)),
+	}
+	if draft.Tags == nil {
+		draft.Tags = []string{}
+	}
+	for _, attachment := range data.Attachments {
+		draft.Attachments = append(draft.Attachments, entity.DraftAttachment{
+			ImageUrl: attachment.ImageUrl,
+			AltText:  attachment.AltText,
+		})
+	}
+
+	return draft
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/schema"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/rank"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/tag"
 	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
-	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
 	postdto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
 	"go.uber.org/zap"
 )
@@ -56,16 +59,55 @@ func (uh *PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// tags are stored normalized, so "#Go" finds posts tagged "go"
+	searchTags, err := tag.NormalizeAll(filter.SearchTag)
+	if err != nil {
+		uh.log.Info("failed to normalize tags", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+	filter.SearchTag = searchTags
+
+	// ranked pages are only read by offset, their order changes as posts age
+	if filter.Sort == "ranked" && filter.Cursor != "" {
+		uh.log.Info("cursor is not supported in the ranked feed")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "cursor is not supported with sort=ranked",
+		}).GenerateResponse(w)
+		return
+	}
+
 	ctx := r.Context()
-	userId := ctx.Value("user_id").(string)
+	userId, _ := ctx.Value("user_id").(string)
 
 	if filter.Limit == 0 {
 		filter.Limit = 5
 	}
 	filter.Offset = filter.Limit * filter.Offset
+	filter.CountLimit = int64(uh.cfg.List.CountLimit)
+	filter.RankCandidates = int64(uh.cfg.Feed.RankCandidates)
+	filter.Ranking = rank.Weights{
+		Recency:  uh.cfg.Feed.RankRecencyWeight,
+		Comments: uh.cfg.Feed.RankCommentWeight,
+		Affinity: uh.cfg.Feed.RankAffinityWeight,
+		HalfLife: uh.cfg.Feed.RankHalfLife,
+	}
 
-	data, count, err := uh.pr.GetPostWithFilter(ctx, filter, userId)
+	data, meta, err := uh.pr.GetPostWithFilter(ctx, filter, userId)
 	if err != nil {
+		if errors.Is(err, cursor.ErrInvalid) {
+			uh.log.Info("failed to decode cursor", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    err.Error(),
+			}).GenerateResponse(w)
+			return
+		}
+
 		uh.log.Info("failed to get post with filter", zap.Error(err))
 		(&response.Response{
 			HttpStatus: http.StatusInternalServerError,
@@ -74,13 +116,30 @@ func (uh *PostHandler) GetPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	postIds := make([]string, 0, len(data))
+	for _, post := range data {
+		postIds = append(postIds, post.ID)
+	}
+
+	comments, next, err := uh.cr.GetFirstPages(ctx, postIds, userId, commentPageSize)
+	if err != nil {
+		uh.log.Info("failed to get post comments", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+	for i := range data {
+		if postComments, ok := comments[data[i].ID]; ok {
+			data[i].Comments = postComments
+		}
+		data[i].NextCommentCursor = next[data[i].ID]
+	}
+
 	(&response.ResponseWithMeta{
 		HttpStatus: http.StatusOK,
 		Data:       data,
-		Meta: metadto.Meta{
-			Limit:  filter.Limit,
-			Offset: filter.Offset,
-			Total:  count,
-		},
+		Meta:       meta,
 	}).GenerateResponseMeta(w)
 }
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	commentdto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/comment"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) GetPostById(w http.ResponseWriter, r *http.Request) {
+	postId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(postId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId, _ := ctx.Value("user_id").(string)
+
+	data, err := uh.pr.GetPostById(ctx, postId, userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("post is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	comments, next, err := uh.cr.GetByPost(ctx, postId, userId, commentdto.CommentFilter{Limit: commentPageSize})
+	if err != nil {
+		uh.log.Info("failed to get post comments", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+	data.Comments = comments
+	data.NextCommentCursor = next
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Get post success",
+		Data:       data,
+	}).GenerateResponse(w)
+}
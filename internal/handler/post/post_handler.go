@@ -1,17 +1,39 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/shafaalafghany/segokuning-social-app/config"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/sanitize"
 	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
 	"github.com/shafaalafghany/segokuning-social-app/pkg/jwt"
 	"go.uber.org/zap"
 )
 
+// maxPostLength bounds postInHtml after sanitizing, since that is what gets
+// stored; escaping and link rel attributes can make it longer than the input.
+const maxPostLength = 500
+
+// commentPageSize is how many comments are embedded in a post; the rest are
+// read from the post's comment endpoint with NextCommentCursor.
+const commentPageSize = 10
+
 type PostHandler struct {
 	ur  interfaces.UserRepository
 	pr  interfaces.PostRepository
+	cr  interfaces.CommentRepository
+	ir  interfaces.ImageRepository
+	rr  interfaces.ReactionRepository
+	spr interfaces.ScheduledPostRepository
+	dr  interfaces.DraftRepository
+	plr interfaces.PollRepository
+	tx  interfaces.Transactor
+	san *sanitize.Policy
 	val *validator.Validate
 	cfg config.Configuration
 	log *zap.Logger
@@ -21,6 +43,13 @@ func NewPostHandler(
 	r chi.Router,
 	ur interfaces.UserRepository,
 	pr interfaces.PostRepository,
+	cr interfaces.CommentRepository,
+	ir interfaces.ImageRepository,
+	rr interfaces.ReactionRepository,
+	spr interfaces.ScheduledPostRepository,
+	dr interfaces.DraftRepository,
+	plr interfaces.PollRepository,
+	tx interfaces.Transactor,
 	val *validator.Validate,
 	cfg config.Configuration,
 	log *zap.Logger,
@@ -28,14 +57,61 @@ func NewPostHandler(
 	fh := &PostHandler{
 		ur:  ur,
 		pr:  pr,
+		cr:  cr,
+		ir:  ir,
+		rr:  rr,
+		spr: spr,
+		dr:  dr,
+		plr: plr,
+		tx:  tx,
+		san: sanitize.NewPolicy(cfg.Post.AllowedTags, cfg.Post.AllowedAttributes),
 		val: val,
 		cfg: cfg,
 		log: log,
 	}
 
 	r.Route("/post", func(r chi.Router) {
-		r.Use(jwt.JwtMiddleware)
-		r.Get("/", fh.GetPost)
-		r.Post("/", fh.CreatePost)
+		// public posts can be read without signing in
+		r.Group(func(r chi.Router) {
+			r.Use(jwt.OptionalJwtMiddleware)
+			r.Get("/", fh.GetPost)
+			r.Get("/{postId}", fh.GetPostById)
+			r.Get("/{postId}/comment", fh.GetPostComment)
+			r.Get("/{postId}/revision", fh.GetPostRevision)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwt.JwtMiddleware)
+			r.Post("/", fh.CreatePost)
+			r.Patch("/{postId}", fh.UpdatePost)
+			r.Delete("/{postId}", fh.DeletePost)
+			r.Post("/{postId}/restore", fh.RestorePost)
+			r.Post("/{postId}/reaction", fh.ReactPost)
+			r.Post("/{postId}/reshare", fh.ResharePost)
+			r.Post("/{postId}/poll/vote", fh.VotePoll)
+			r.Put("/{postId}/poll/vote", fh.ChangePollVote)
+			r.Get("/scheduled", fh.GetScheduledPosts)
+			r.Patch("/scheduled/{scheduledPostId}", fh.UpdateScheduledPost)
+			r.Delete("/scheduled/{scheduledPostId}", fh.CancelScheduledPost)
+			r.Get("/draft", fh.GetDrafts)
+			r.Post("/draft", fh.CreateDraft)
+			r.Put("/draft/{draftId}", fh.UpdateDraft)
+			r.Post("/draft/{draftId}/publish", fh.PublishDraft)
+		})
 	})
 }
+
+// tooLong reports whether sanitized post html is over maxPostLength, writing
+// the error response when it is.
+func (uh *PostHandler) tooLong(w http.ResponseWriter, html string) bool {
+	if utf8.RuneCountInString(html) <= maxPostLength {
+		return false
+	}
+
+	uh.log.Info("post is too long", zap.Int("length", utf8.RuneCountInString(html)))
+	(&response.Response{
+		HttpStatus: http.StatusBadRequest,
+		Message:    fmt.Sprintf("postInHtml must be at most %d characters", maxPostLength),
+	}).GenerateResponse(w)
+	return true
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) VotePoll(w http.ResponseWriter, r *http.Request) {
+	uh.vote(w, r, false)
+}
+
+func (uh *PostHandler) ChangePollVote(w http.ResponseWriter, r *http.Request) {
+	uh.vote(w, r, true)
+}
+
+// vote records the caller's vote on the poll of a post they can see, or
+// replaces it when change is set, and responds with the updated poll.
+func (uh *PostHandler) vote(w http.ResponseWriter, r *http.Request, change bool) {
+	var (
+		data dto.PollVote
+	)
+
+	postId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(postId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	visible, err := uh.pr.IsVisible(ctx, postId, userId)
+	if err != nil {
+		uh.log.Info("failed to check post visibility", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !visible {
+		uh.log.Info("you cannot vote on this post")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "You cannot vote on this post",
+		}).GenerateResponse(w)
+		return
+	}
+
+	poll, err := uh.plr.FindByPost(ctx, postId, userId)
+	if err != nil {
+		uh.log.Info("failed to get poll", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if poll == nil {
+		uh.log.Info("poll is not found")
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Poll not found",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if poll.Closed {
+		uh.log.Info("poll is closed")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Poll is closed",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !poll.Multiple && len(data.OptionIds) > 1 {
+		uh.log.Info("too many options for a single choice poll")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Only one option can be chosen in this poll",
+		}).GenerateResponse(w)
+		return
+	}
+
+	options := make(map[string]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		options[option.ID] = true
+	}
+	for _, optionId := range data.OptionIds {
+		if !options[optionId] {
+			uh.log.Info("option is not in the poll", zap.String("optionId", optionId))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    "Option not found in this poll",
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	var recorded bool
+	if change {
+		recorded, err = uh.plr.ChangeVote(ctx, postId, userId, data.OptionIds)
+	} else {
+		recorded, err = uh.plr.Vote(ctx, postId, userId, data.OptionIds)
+	}
+	if errors.Is(err, entity.ErrPollClosed) {
+		uh.log.Info("poll closed before the vote was recorded")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Poll is closed",
+		}).GenerateResponse(w)
+		return
+	}
+	if err != nil {
+		uh.log.Info("failed to vote", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !recorded && change {
+		uh.log.Info("there is no vote to change")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "You have not voted in this poll yet",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !recorded {
+		uh.log.Info("already voted")
+		(&response.Response{
+			HttpStatus: http.StatusConflict,
+			Message:    "You have already voted in this poll, change your vote instead",
+		}).GenerateResponse(w)
+		return
+	}
+
+	poll, err = uh.plr.FindByPost(ctx, postId, userId)
+	if err != nil {
+		uh.log.Info("failed to get poll", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Vote success",
+		Data:       poll,
+	}).GenerateResponse(w)
+}
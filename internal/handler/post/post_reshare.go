@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/mention"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/tag"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) ResharePost(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		data   dto.ReshareCreate
+	)
+
+	originalId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(originalId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+
+	if _, err := uh.pr.FindById(ctx, originalId); err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("post is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	visible, err := uh.pr.IsVisible(ctx, originalId, userId)
+	if err != nil {
+		uh.log.Info("failed to check post visibility", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !visible {
+		uh.log.Info("you cannot reshare this post")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "You cannot reshare this post",
+		}).GenerateResponse(w)
+		return
+	}
+
+	tags, err := tag.NormalizeAll(data.Tags)
+	if err != nil {
+		uh.log.Info("failed to normalize tags", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+	data.Tags = tags
+
+	// the commentary of a quote goes through the same sanitizer as posts
+	data.PostInHtml = uh.san.Sanitize(data.PostInHtml)
+	if uh.tooLong(w, data.PostInHtml) {
+		return
+	}
+	if data.Visibility == "" {
+		data.Visibility = entity.VisibilityFriends
+	}
+
+	postEntity := entity.Post{
+		ID:          uuid.NewString(),
+		PostInHtml:  data.PostInHtml,
+		Tags:        data.Tags,
+		Visibility:  data.Visibility,
+		Attachments: []entity.Attachment{},
+		Mentions:    mentions(mention.ExtractHtml(data.PostInHtml)),
+		ReshareOf:   originalId,
+	}
+
+	if err := uh.pr.Insert(ctx, postEntity, userId, uh.cfg.Post.FanoutLimit); err != nil {
+		uh.log.Info("failed to insert data", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Reshare post success",
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) RestorePost(w http.ResponseWriter, r *http.Request) {
+	postId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(postId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	post, restorable, err := uh.pr.FindDeletedById(ctx, postId, uh.cfg.Post.RestoreWindow)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("deleted post is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Deleted post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get deleted post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if post.UserId != userId {
+		uh.log.Info("only the author can restore this post")
+		(&response.Response{
+			HttpStatus: http.StatusForbidden,
+			Message:    "Only the author can restore this post",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !restorable {
+		uh.log.Info("restore window of the post has passed")
+		(&response.Response{
+			HttpStatus: http.StatusGone,
+			Message:    "Post can no longer be restored",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.pr.Restore(ctx, postId); err != nil {
+		uh.log.Info("failed to restore post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Restore post success",
+	}).GenerateResponse(w)
+}
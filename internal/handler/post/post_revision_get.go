@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) GetPostRevision(w http.ResponseWriter, r *http.Request) {
+	postId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(postId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId, _ := ctx.Value("user_id").(string)
+
+	visible, err := uh.pr.IsVisible(ctx, postId, userId)
+	if err != nil {
+		uh.log.Info("failed to check post visibility", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !visible {
+		uh.log.Info("post is not found")
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Post not found",
+		}).GenerateResponse(w)
+		return
+	}
+
+	data, err := uh.pr.GetRevisions(ctx, postId)
+	if err != nil {
+		uh.log.Info("failed to get post revisions", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Get post revisions success",
+		Data:       data,
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"go.uber.org/zap"
+)
+
+// schedulePost stores post to be published by the scheduler at publishAt.
+func (uh *PostHandler) schedulePost(ctx context.Context, w http.ResponseWriter, userId string, post entity.Post, publishAt time.Time) {
+	if post.Poll != nil {
+		uh.log.Info("poll cannot be scheduled")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Posts with a poll cannot be scheduled",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if !publishAt.After(time.Now()) {
+		uh.log.Info("publish time is not in the future")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "publishAt must be in the future",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.spr.Insert(ctx, scheduledPost(post, userId), publishAt); err != nil {
+		uh.log.Info("failed to schedule post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Schedule post success",
+	}).GenerateResponse(w)
+}
+
+func (uh *PostHandler) GetScheduledPosts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	data, err := uh.spr.GetByUser(ctx, userId)
+	if err != nil {
+		uh.log.Info("failed to get scheduled posts", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Data:       data,
+	}).GenerateResponse(w)
+}
+
+// UpdateScheduledPost replaces a scheduled post with the request body, which
+// is validated like a new post and must keep a publishAt.
+func (uh *PostHandler) UpdateScheduledPost(w http.ResponseWriter, r *http.Request) {
+	var (
+		data dto.PostCreate
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	if data.PublishAt == nil || !data.PublishAt.After(time.Now()) {
+		uh.log.Info("publish time is not in the future")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "publishAt must be in the future",
+		}).GenerateResponse(w)
+		return
+	}
+
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	scheduled, ok := uh.ownedScheduledPost(ctx, w, chi.URLParam(r, "scheduledPostId"), userId)
+	if !ok {
+		return
+	}
+
+	post, ok := uh.newPost(ctx, w, userId, data)
+	if !ok {
+		return
+	}
+	post.ID = scheduled.ID
+
+	if post.Poll != nil {
+		uh.log.Info("poll cannot be scheduled")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "Posts with a poll cannot be scheduled",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.spr.Update(ctx, scheduledPost(post, userId), *data.PublishAt); err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("scheduled post was published or cancelled", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Scheduled post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to update scheduled post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Update scheduled post success",
+	}).GenerateResponse(w)
+}
+
+func (uh *PostHandler) CancelScheduledPost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userId := ctx.Value("user_id").(string)
+
+	scheduled, ok := uh.ownedScheduledPost(ctx, w, chi.URLParam(r, "scheduledPostId"), userId)
+	if !ok {
+		return
+	}
+
+	if err := uh.spr.Delete(ctx, scheduled.ID); err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("scheduled post was published or cancelled", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Scheduled post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to cancel scheduled post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Cancel scheduled post success",
+	}).GenerateResponse(w)
+}
+
+// ownedScheduledPost loads a scheduled post of userId. Posts of other users
+// are reported as not found.
+func (uh *PostHandler) ownedScheduledPost(ctx context.Context, w http.ResponseWriter, id, userId string) (post entity.ScheduledPost, ok bool) {
+	if err := validation.UuidValidation(id); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return post, false
+	}
+
+	post, err := uh.spr.FindById(ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		uh.log.Info("failed to get scheduled post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return post, false
+	}
+
+	if err == pgx.ErrNoRows || post.UserId != userId {
+		uh.log.Info("scheduled post is not found")
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    "Scheduled post not found",
+		}).GenerateResponse(w)
+		return post, false
+	}
+
+	return post, true
+}
+
+func scheduledPost(post entity.Post, userId string) entity.ScheduledPost {
+	return entity.ScheduledPost{
+		ID:          post.ID,
+		UserId:      userId,
+		PostInHtml:  post.PostInHtml,
+		Tags:        post.Tags,
+		Visibility:  post.Visibility,
+		Attachments: post.Attachments,
+	}
+}
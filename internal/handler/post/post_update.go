@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/mention"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/tag"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+	"go.uber.org/zap"
+)
+
+func (uh *PostHandler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	var (
+		userId string
+		data   dto.PostUpdate
+	)
+
+	postId := chi.URLParam(r, "postId")
+	if err := validation.UuidValidation(postId); err != nil {
+		uh.log.Info("failed to validate uuid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusNotFound,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		uh.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "required fields are missing or invalid",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := uh.val.Struct(data); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			uh.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	tags, err := tag.NormalizeAll(data.Tags)
+	if err != nil {
+		uh.log.Info("failed to normalize tags", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+	data.Tags = tags
+
+	// the sanitized html is what gets stored and served back to readers
+	data.PostInHtml = uh.san.Sanitize(data.PostInHtml)
+	if strings.TrimSpace(data.PostInHtml) == "" {
+		uh.log.Info("post has no allowed content")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "postInHtml has no allowed content",
+		}).GenerateResponse(w)
+		return
+	}
+	if uh.tooLong(w, data.PostInHtml) {
+		return
+	}
+
+	ctx := r.Context()
+	userId = ctx.Value("user_id").(string)
+
+	post, err := uh.pr.FindById(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			uh.log.Info("post is not found", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusNotFound,
+				Message:    "Post not found",
+			}).GenerateResponse(w)
+			return
+		}
+
+		uh.log.Info("failed to get post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if post.UserId != userId {
+		uh.log.Info("only the author can edit this post")
+		(&response.Response{
+			HttpStatus: http.StatusForbidden,
+			Message:    "Only the author can edit this post",
+		}).GenerateResponse(w)
+		return
+	}
+
+	post.PostInHtml = data.PostInHtml
+	post.Tags = data.Tags
+	post.Mentions = mentions(mention.ExtractHtml(data.PostInHtml))
+
+	if err := uh.pr.Update(ctx, post, uuid.NewString()); err != nil {
+		uh.log.Info("failed to update post", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Update post success",
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/tag"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/tag"
+	"go.uber.org/zap"
+)
+
+func (th *TagHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter dto.AutocompleteFilter
+	)
+
+	if err := r.ParseForm(); err != nil {
+		th.log.Info("failed to parse form", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := validation.ValidateParams(r, filter); err != nil {
+		th.log.Info("failed to validate params", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := schema.NewDecoder().Decode(&filter, r.Form); err != nil {
+		th.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := th.val.Struct(filter); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			th.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	prefix := tag.Normalize(filter.Query)
+	if prefix == "" {
+		th.log.Info("autocomplete query is empty")
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "q should contain a tag",
+		}).GenerateResponse(w)
+		return
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+
+	ctx := r.Context()
+	userId, _ := ctx.Value("user_id").(string)
+
+	data, err := th.tr.Autocomplete(ctx, prefix, filter.Limit, userId, th.cfg.Tag.RecentWindow)
+	if err != nil {
+		th.log.Info("failed to autocomplete tags", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Autocomplete tags success",
+		Data:       data,
+	}).GenerateResponse(w)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/shafaalafghany/segokuning-social-app/config"
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/jwt"
+	"go.uber.org/zap"
+)
+
+type TagHandler struct {
+	tr  interfaces.TagRepository
+	pr  interfaces.PostRepository
+	val *validator.Validate
+	cfg config.Configuration
+	log *zap.Logger
+}
+
+func NewTagHandler(
+	r chi.Router,
+	tr interfaces.TagRepository,
+	pr interfaces.PostRepository,
+	val *validator.Validate,
+	cfg config.Configuration,
+	log *zap.Logger,
+) {
+	th := &TagHandler{
+		tr:  tr,
+		pr:  pr,
+		val: val,
+		cfg: cfg,
+		log: log,
+	}
+
+	r.Route("/tag", func(r chi.Router) {
+		r.Use(jwt.OptionalJwtMiddleware)
+		r.Get("/trending", th.GetTrending)
+		r.Get("/autocomplete", th.Autocomplete)
+		r.Get("/{tag}", th.GetTagPosts)
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/tag"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dtopost "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/tag"
+	"go.uber.org/zap"
+)
+
+func (th *TagHandler) GetTagPosts(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter dto.TagPostFilter
+	)
+
+	rawTag, err := url.PathUnescape(chi.URLParam(r, "tag"))
+	if err != nil {
+		th.log.Info("failed to unescape tag", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	tags, err := tag.NormalizeAll([]string{rawTag})
+	if err != nil {
+		th.log.Info("failed to normalize tag", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		th.log.Info("failed to parse form", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := validation.ValidateParams(r, filter); err != nil {
+		th.log.Info("failed to validate params", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := schema.NewDecoder().Decode(&filter, r.Form); err != nil {
+		th.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := th.val.Struct(filter); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			th.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	userId, _ := ctx.Value("user_id").(string)
+
+	if filter.Limit == 0 {
+		filter.Limit = 5
+	}
+	filter.Offset = filter.Limit * filter.Offset
+
+	posts, meta, err := th.pr.GetPostWithFilter(ctx, dtopost.PostFilter{
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+		Cursor: filter.Cursor,
+		Tag:    tags[0],
+	}, userId)
+	if err != nil {
+		if errors.Is(err, cursor.ErrInvalid) {
+			th.log.Info("failed to decode cursor", zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    err.Error(),
+			}).GenerateResponse(w)
+			return
+		}
+
+		th.log.Info("failed to get tag posts", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	count, err := th.pr.CountByTag(ctx, tags[0], userId)
+	if err != nil {
+		th.log.Info("failed to count tag posts", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	meta.Total = count
+	(&response.ResponseWithMeta{
+		HttpStatus: http.StatusOK,
+		Data: dto.TagPage{
+			Tag:       tags[0],
+			PostCount: count,
+			Posts:     posts,
+		},
+		Meta: meta,
+	}).GenerateResponseMeta(w)
+}
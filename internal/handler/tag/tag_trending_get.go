@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/schema"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/response"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/validation"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/tag"
+	"go.uber.org/zap"
+)
+
+func (th *TagHandler) GetTrending(w http.ResponseWriter, r *http.Request) {
+	var (
+		filter dto.TrendingFilter
+	)
+
+	if err := r.ParseForm(); err != nil {
+		th.log.Info("failed to parse form", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := validation.ValidateParams(r, filter); err != nil {
+		th.log.Info("failed to validate params", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := schema.NewDecoder().Decode(&filter, r.Form); err != nil {
+		th.log.Info("required fields are missing or invalid", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if err := th.val.Struct(filter); err != nil {
+		validationErrors := err.(validator.ValidationErrors)
+		for _, e := range validationErrors {
+			th.log.Info(validation.CustomError(e), zap.Error(err))
+			(&response.Response{
+				HttpStatus: http.StatusBadRequest,
+				Message:    validation.CustomError(e),
+			}).GenerateResponse(w)
+			return
+		}
+	}
+
+	if filter.Window == "" {
+		filter.Window = "24h"
+	}
+	window, ok := th.cfg.Tag.TrendingWindows[filter.Window]
+	if !ok {
+		windows := make([]string, 0, len(th.cfg.Tag.TrendingWindows))
+		for name := range th.cfg.Tag.TrendingWindows {
+			windows = append(windows, name)
+		}
+		sort.Strings(windows)
+
+		th.log.Info("unsupported trending window", zap.String("window", filter.Window))
+		(&response.Response{
+			HttpStatus: http.StatusBadRequest,
+			Message:    "window must be one of " + strings.Join(windows, ", "),
+		}).GenerateResponse(w)
+		return
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+
+	ctx := r.Context()
+	userId, _ := ctx.Value("user_id").(string)
+
+	data, err := th.tr.GetTrending(ctx, window, filter.Limit, userId)
+	if err != nil {
+		th.log.Info("failed to get trending tags", zap.Error(err))
+		(&response.Response{
+			HttpStatus: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}).GenerateResponse(w)
+		return
+	}
+
+	(&response.Response{
+		HttpStatus: http.StatusOK,
+		Message:    "Get trending tags success",
+		Data:       data,
+	}).GenerateResponse(w)
+}
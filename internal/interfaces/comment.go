@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/comment"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
 )
 
@@ -10,5 +11,9 @@ import (
 type (
 	CommentRepository interface {
 		Insert(context.Context, entity.Comment) error
+		FindById(context.Context, string) (entity.Comment, error)
+		CountByPost(context.Context, string) (int64, error)
+		GetByPost(context.Context, string, string, dto.CommentFilter) ([]dto.Comment, string, error)
+		GetFirstPages(context.Context, []string, string, int64) (map[string][]dto.Comment, map[string]string, error)
 	}
 )
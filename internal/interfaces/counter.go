@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	CounterRepository interface {
+		Reconcile(context.Context, entity.Counter, string, int, bool) (entity.CounterBatch, error)
+	}
+)
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	DraftRepository interface {
+		Insert(context.Context, entity.Draft) (entity.Draft, error)
+		GetByUser(context.Context, string) ([]entity.Draft, error)
+		FindById(context.Context, string) (entity.Draft, error)
+		Update(context.Context, entity.Draft) (entity.Draft, error)
+		Delete(context.Context, string, int64) error
+		Expire(context.Context, time.Duration, int) (int64, error)
+	}
+)
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/follow"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	FollowRepository interface {
+		FindByRelation(context.Context, string, string) (int, error)
+		Insert(context.Context, string, string) error
+		Delete(context.Context, string, string) error
+		GetFollowers(context.Context, string, dto.FollowFilter) ([]entity.User, int64, error)
+		GetFollowing(context.Context, string, dto.FollowFilter) ([]entity.User, int64, error)
+	}
+)
@@ -2,6 +2,9 @@ package interfaces
 
 import (
 	"context"
+
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/friend"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
 )
 
 // Translation -.
@@ -10,5 +13,6 @@ type (
 		FindByRelation(context.Context, string, string) (int, error)
 		Insert(context.Context, string, string) error
 		Delete(context.Context, string, string) error
+		GetMutualFriends(context.Context, string, dto.MutualFriendFilter) ([]entity.User, int64, error)
 	}
 )
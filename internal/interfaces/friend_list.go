@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	FriendListRepository interface {
+		Insert(context.Context, entity.FriendList) error
+		FindById(context.Context, string) (entity.FriendList, error)
+		GetByUser(context.Context, string) ([]entity.FriendList, error)
+		Delete(context.Context, string) error
+		NameCheck(context.Context, string, string) (int64, error)
+		InsertMember(context.Context, string, string) error
+		DeleteMember(context.Context, string, string) error
+	}
+)
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	ImageRepository interface {
+		Insert(context.Context, entity.Image) error
+		FindByUrls(context.Context, string, []string) (map[string]string, error)
+	}
+)
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	PollRepository interface {
+		FindByPost(context.Context, string, string) (*entity.Poll, error)
+		Vote(context.Context, string, string, []string) (bool, error)
+		ChangeVote(context.Context, string, string, []string) (bool, error)
+	}
+)
@@ -2,7 +2,9 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
+	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
 	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
 )
@@ -10,8 +12,17 @@ import (
 // Translation -.
 type (
 	PostRepository interface {
-		Insert(context.Context, entity.Post, string) error
-		GetPostWithFilter(context.Context, dto.PostFilter, string) ([]dto.Post, int64, error)
+		Insert(context.Context, entity.Post, string, int) error
+		GetPostWithFilter(context.Context, dto.PostFilter, string) ([]dto.Post, metadto.Meta, error)
 		FindById(context.Context, string) (entity.Post, error)
+		GetPostById(context.Context, string, string) (dto.Post, error)
+		IsVisible(context.Context, string, string) (bool, error)
+		CountByTag(context.Context, string, string) (int64, error)
+		Update(context.Context, entity.Post, string) error
+		GetRevisions(context.Context, string) ([]entity.PostRevision, error)
+		Delete(context.Context, string) error
+		FindDeletedById(context.Context, string, time.Duration) (entity.Post, bool, error)
+		Restore(context.Context, string) error
+		Purge(context.Context, time.Duration, int) (int64, []string, error)
 	}
 )
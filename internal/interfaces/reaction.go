@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	ReactionRepository interface {
+		Toggle(context.Context, entity.Reaction) (string, error)
+	}
+)
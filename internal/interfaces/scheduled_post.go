@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	ScheduledPostRepository interface {
+		Insert(context.Context, entity.ScheduledPost, time.Time) error
+		GetByUser(context.Context, string) ([]entity.ScheduledPost, error)
+		FindById(context.Context, string) (entity.ScheduledPost, error)
+		Update(context.Context, entity.ScheduledPost, time.Time) error
+		Delete(context.Context, string) error
+		ClaimDue(context.Context, int) (entity.ScheduledPost, error)
+		Fail(context.Context, string, string, time.Time) error
+	}
+)
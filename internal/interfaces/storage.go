@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"io"
+)
+
+// Translation -.
+type (
+	Storage interface {
+		Upload(string, io.ReadSeeker) (string, error)
+		Delete(string) error
+	}
+)
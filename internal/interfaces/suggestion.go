@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/friend"
+)
+
+// Translation -.
+type (
+	SuggestionRepository interface {
+		GetUserIds(context.Context, string, int) ([]string, error)
+		Refresh(context.Context, string, int) error
+		GetSuggestions(context.Context, string, dto.SuggestionFilter) ([]dto.Suggestion, int64, error)
+	}
+)
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+)
+
+// Translation -.
+type (
+	TagRepository interface {
+		GetTrending(context.Context, time.Duration, int64, string) ([]entity.TrendingTag, error)
+		PruneCounts(context.Context, time.Duration) (int64, error)
+		Autocomplete(context.Context, string, int64, string, time.Duration) ([]entity.TagUsage, error)
+	}
+)
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// Translation -.
+type (
+	Transactor interface {
+		WithinTransaction(context.Context, func(context.Context) error) error
+	}
+)
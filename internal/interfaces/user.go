@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 
+	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
 	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/user"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
 )
@@ -11,7 +12,7 @@ import (
 type (
 	UserRepository interface {
 		Get(context.Context, entity.User) error
-		GetUserWithFilter(context.Context, string, dto.UserFilter) ([]entity.User, int64, error)
+		GetUserWithFilter(context.Context, string, dto.UserFilter) ([]entity.User, metadto.Meta, error)
 		FindById(context.Context, string) (*entity.User, error)
 		FindByEmail(context.Context, string) (*entity.User, error)
 		FindByPhone(context.Context, string) (*entity.User, error)
@@ -0,0 +1,111 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+var (
+	counterScanned = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "segokuning_counter_rows_scanned_total",
+		Help: "Rows checked by the counter reconciliation job.",
+	}, []string{"counter"})
+
+	counterDrifted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "segokuning_counter_drift_found_total",
+		Help: "Rows whose stored count differed from the source table.",
+	}, []string{"counter"})
+
+	counterCorrected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "segokuning_counter_drift_corrected_total",
+		Help: "Rows whose stored count was overwritten with the recomputed value.",
+	}, []string{"counter"})
+
+	counterLastRun = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "segokuning_counter_last_run_timestamp_seconds",
+		Help: "Unix time of the last finished reconciliation of a counter.",
+	}, []string{"counter"})
+)
+
+// CounterJob recomputes denormalized counters from their source tables and
+// fixes the rows that drifted.
+type CounterJob struct {
+	cr        interfaces.CounterRepository
+	counters  []entity.Counter
+	batchSize int
+	dryRun    bool
+	log       *zap.Logger
+}
+
+func NewCounterJob(cr interfaces.CounterRepository, counters []entity.Counter, batchSize int, dryRun bool, log *zap.Logger) *CounterJob {
+	return &CounterJob{
+		cr:        cr,
+		counters:  counters,
+		batchSize: batchSize,
+		dryRun:    dryRun,
+		log:       log,
+	}
+}
+
+func (cj *CounterJob) Name() string {
+	return "counter_reconcile"
+}
+
+func (cj *CounterJob) Run(ctx context.Context) error {
+	_, err := cj.Reconcile(ctx)
+	return err
+}
+
+// Reconcile walks every configured counter in batches and returns what it
+// found per counter.
+func (cj *CounterJob) Reconcile(ctx context.Context) ([]entity.CounterResult, error) {
+	results := make([]entity.CounterResult, 0, len(cj.counters))
+	for _, counter := range cj.counters {
+		result, err := cj.reconcileCounter(ctx, counter)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("failed to reconcile %s: %w", counter.Name, err)
+		}
+
+		cj.log.Info("counter reconciled",
+			zap.String("counter", result.Counter),
+			zap.Int64("scanned", result.Scanned),
+			zap.Int64("drifted", result.Drifted),
+			zap.Int64("corrected", result.Corrected))
+	}
+
+	return results, nil
+}
+
+func (cj *CounterJob) reconcileCounter(ctx context.Context, counter entity.Counter) (entity.CounterResult, error) {
+	result := entity.CounterResult{Counter: counter.Name}
+	after := ""
+	for {
+		batch, err := cj.cr.Reconcile(ctx, counter, after, cj.batchSize, cj.dryRun)
+		if err != nil {
+			return result, err
+		}
+
+		result.Scanned += batch.Scanned
+		result.Drifted += batch.Drifted
+		result.Corrected += batch.Corrected
+		counterScanned.WithLabelValues(counter.Name).Add(float64(batch.Scanned))
+		counterDrifted.WithLabelValues(counter.Name).Add(float64(batch.Drifted))
+		counterCorrected.WithLabelValues(counter.Name).Add(float64(batch.Corrected))
+
+		if batch.Scanned < int64(cj.batchSize) || batch.LastId == "" {
+			break
+		}
+		after = batch.LastId
+	}
+
+	counterLastRun.WithLabelValues(counter.Name).Set(float64(time.Now().Unix()))
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// DraftExpireJob removes drafts that have not been saved for longer than
+// their time to live.
+type DraftExpireJob struct {
+	dr        interfaces.DraftRepository
+	ttl       time.Duration
+	batchSize int
+	log       *zap.Logger
+}
+
+func NewDraftExpireJob(dr interfaces.DraftRepository, ttl time.Duration, batchSize int, log *zap.Logger) *DraftExpireJob {
+	return &DraftExpireJob{
+		dr:        dr,
+		ttl:       ttl,
+		batchSize: batchSize,
+		log:       log,
+	}
+}
+
+func (dj *DraftExpireJob) Name() string {
+	return "draft_expire"
+}
+
+func (dj *DraftExpireJob) Run(ctx context.Context) error {
+	var total int64
+	for {
+		expired, err := dj.dr.Expire(ctx, dj.ttl, dj.batchSize)
+		if err != nil {
+			return err
+		}
+
+		total += expired
+		if expired < int64(dj.batchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		dj.log.Info("expired drafts", zap.Int64("count", total))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is a unit of background work that is run periodically.
+type Job interface {
+	Name() string
+	Run(context.Context) error
+}
+
+// Schedule runs j on every interval tick until ctx is cancelled. Failures are
+// logged and retried on the next tick. A non-positive interval disables j.
+func Schedule(ctx context.Context, log *zap.Logger, interval time.Duration, j Job) {
+	if interval <= 0 {
+		log.Info("job is disabled", zap.String("job", j.Name()))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run(ctx, log, j)
+			}
+		}
+	}()
+}
+
+func run(ctx context.Context, log *zap.Logger, j Job) {
+	start := time.Now()
+	if err := j.Run(ctx); err != nil && ctx.Err() == nil {
+		log.Error("job failed", zap.String("job", j.Name()), zap.Error(err))
+		return
+	}
+
+	log.Info("job finished", zap.String("job", j.Name()), zap.Duration("took", time.Since(start)))
+}
@@ -0,0 +1,113 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/mention"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// PostPublishJob publishes scheduled posts that are due. Each post is claimed,
+// published and removed from the schedule in one transaction, so a post is
+// published exactly once however many instances run the job, and posts that
+// came due while no instance was running are published on the next run. A
+// post that fails to publish is retried later with a growing backoff, up to
+// maxAttempts times, so it does not hold up the posts due after it.
+type PostPublishJob struct {
+	tx          interfaces.Transactor
+	spr         interfaces.ScheduledPostRepository
+	pr          interfaces.PostRepository
+	fanoutLimit int
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	log         *zap.Logger
+}
+
+func NewPostPublishJob(tx interfaces.Transactor, spr interfaces.ScheduledPostRepository, pr interfaces.PostRepository, fanoutLimit, batchSize, maxAttempts int, backoff time.Duration, log *zap.Logger) *PostPublishJob {
+	return &PostPublishJob{
+		tx:          tx,
+		spr:         spr,
+		pr:          pr,
+		fanoutLimit: fanoutLimit,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		log:         log,
+	}
+}
+
+func (pj *PostPublishJob) Name() string {
+	return "post_publish"
+}
+
+func (pj *PostPublishJob) Run(ctx context.Context) error {
+	var published, failed int
+	for published+failed < pj.batchSize {
+		var scheduled entity.ScheduledPost
+		done := false
+		err := pj.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			scheduled, err = pj.spr.ClaimDue(ctx, pj.maxAttempts)
+			if err != nil {
+				if err == pgx.ErrNoRows {
+					done = true
+					return nil
+				}
+				return err
+			}
+
+			// mentions are resolved now, against who can see the post when it
+			// goes out
+			mentions := make([]entity.Mention, 0)
+			for _, userId := range mention.ExtractHtml(scheduled.PostInHtml) {
+				mentions = append(mentions, entity.Mention{UserId: userId})
+			}
+
+			post := entity.Post{
+				ID:          scheduled.ID,
+				PostInHtml:  scheduled.PostInHtml,
+				Tags:        scheduled.Tags,
+				Visibility:  scheduled.Visibility,
+				Attachments: scheduled.Attachments,
+				Mentions:    mentions,
+			}
+			if err := pj.pr.Insert(ctx, post, scheduled.UserId, pj.fanoutLimit); err != nil {
+				return err
+			}
+
+			return pj.spr.Delete(ctx, scheduled.ID)
+		})
+		if err != nil {
+			// nothing was claimed, so there is no post to set aside
+			if scheduled.ID == "" {
+				return err
+			}
+
+			retryAt := time.Now().Add(pj.backoff << scheduled.Attempts)
+			pj.log.Info("failed to publish scheduled post",
+				zap.String("scheduledPostId", scheduled.ID),
+				zap.Int("attempt", scheduled.Attempts+1),
+				zap.Time("retryAt", retryAt),
+				zap.Error(err))
+			if err := pj.spr.Fail(ctx, scheduled.ID, err.Error(), retryAt); err != nil {
+				return err
+			}
+			failed++
+			continue
+		}
+		if done {
+			break
+		}
+		published++
+	}
+
+	if published > 0 || failed > 0 {
+		pj.log.Info("published scheduled posts", zap.Int("count", published), zap.Int("failed", failed))
+	}
+	return nil
+}
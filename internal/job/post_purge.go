@@ -0,0 +1,60 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// PostPurgeJob permanently removes posts whose restore window has passed,
+// along with their attached media.
+type PostPurgeJob struct {
+	pr            interfaces.PostRepository
+	st            interfaces.Storage
+	restoreWindow time.Duration
+	batchSize     int
+	log           *zap.Logger
+}
+
+func NewPostPurgeJob(pr interfaces.PostRepository, st interfaces.Storage, restoreWindow time.Duration, batchSize int, log *zap.Logger) *PostPurgeJob {
+	return &PostPurgeJob{
+		pr:            pr,
+		st:            st,
+		restoreWindow: restoreWindow,
+		batchSize:     batchSize,
+		log:           log,
+	}
+}
+
+func (pj *PostPurgeJob) Name() string {
+	return "post_purge"
+}
+
+func (pj *PostPurgeJob) Run(ctx context.Context) error {
+	var total int64
+	for {
+		purged, media, err := pj.pr.Purge(ctx, pj.restoreWindow, pj.batchSize)
+		if err != nil {
+			return err
+		}
+
+		// the rows are gone already, so a failed delete only leaves an orphaned object
+		for _, url := range media {
+			if err := pj.st.Delete(url); err != nil {
+				pj.log.Error("failed to delete purged media", zap.String("url", url), zap.Error(err))
+			}
+		}
+
+		total += purged
+		if purged < int64(pj.batchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		pj.log.Info("purged deleted posts", zap.Int64("count", total))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// SuggestionJob precomputes "people you may know" for every user.
+type SuggestionJob struct {
+	sr        interfaces.SuggestionRepository
+	batchSize int
+	limit     int
+	log       *zap.Logger
+}
+
+func NewSuggestionJob(sr interfaces.SuggestionRepository, batchSize, limit int, log *zap.Logger) *SuggestionJob {
+	return &SuggestionJob{
+		sr:        sr,
+		batchSize: batchSize,
+		limit:     limit,
+		log:       log,
+	}
+}
+
+func (sj *SuggestionJob) Name() string {
+	return "friend_suggestion"
+}
+
+func (sj *SuggestionJob) Run(ctx context.Context) error {
+	after := ""
+	for {
+		ids, err := sj.sr.GetUserIds(ctx, after, sj.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, id := range ids {
+			if err := sj.sr.Refresh(ctx, id, sj.limit); err != nil {
+				// one bad user should not stop the whole run
+				sj.log.Info("failed to refresh friend suggestions", zap.String("user_id", id), zap.Error(err))
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+			}
+		}
+		after = ids[len(ids)-1]
+	}
+}
@@ -0,0 +1,40 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	interfaces "github.com/shafaalafghany/segokuning-social-app/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// TagPruneJob drops hourly tag aggregates too old for any trending window.
+type TagPruneJob struct {
+	tr        interfaces.TagRepository
+	retention time.Duration
+	log       *zap.Logger
+}
+
+func NewTagPruneJob(tr interfaces.TagRepository, retention time.Duration, log *zap.Logger) *TagPruneJob {
+	return &TagPruneJob{
+		tr:        tr,
+		retention: retention,
+		log:       log,
+	}
+}
+
+func (tj *TagPruneJob) Name() string {
+	return "tag_prune"
+}
+
+func (tj *TagPruneJob) Run(ctx context.Context) error {
+	pruned, err := tj.tr.PruneCounts(ctx, tj.retention)
+	if err != nil {
+		return err
+	}
+
+	if pruned > 0 {
+		tj.log.Info("pruned tag aggregates", zap.Int64("count", pruned))
+	}
+	return nil
+}
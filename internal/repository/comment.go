@@ -2,10 +2,16 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/mention"
+	dtocomment "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/comment"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
 	"go.uber.org/zap"
 )
 
@@ -22,10 +28,224 @@ func NewCommentRepo(db *pgxpool.Pool, log *zap.Logger) *CommentRepository {
 }
 
 func (pr *CommentRepository) Insert(ctx context.Context, data entity.Comment) error {
-	sql := `INSERT INTO comments (id, user_id, comment, post_id) VALUES ($1,$2,$3,$4)`
-	if _, err := pr.db.Exec(ctx, sql, data.ID, data.UserId, data.Comment, data.PostId); err != nil {
+	return db.WithinTransaction(ctx, pr.db, func(ctx context.Context) error {
+		sql := `INSERT INTO comments (id, user_id, comment, post_id) VALUES ($1,$2,$3,$4)`
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, data.ID, data.UserId, data.Comment, data.PostId); err != nil {
+			return err
+		}
+
+		return insertMentions(ctx, pr.db, data.PostId, entity.MentionTargetComment, data.ID, mentionIds(data.Mentions))
+	})
+}
+
+func (pr *CommentRepository) FindById(ctx context.Context, commentId string) (entity.Comment, error) {
+	comment := entity.Comment{}
+	sql := `SELECT id, user_id, comment, post_id, created_at FROM comments WHERE id = $1`
+	err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, commentId).Scan(&comment.ID, &comment.UserId, &comment.Comment, &comment.PostId, &comment.CreatedAt)
+	if err != nil {
+		return comment, err
+	}
+
+	return comment, nil
+}
+
+// CountByPost counts the comments of a post.
+func (pr *CommentRepository) CountByPost(ctx context.Context, postId string) (int64, error) {
+	var count int64
+	sql := `SELECT count(id) FROM comments WHERE post_id = $1`
+	if err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, postId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetByPost returns comments of a post oldest first, starting after the given
+// cursor, along with the cursor of the next page ("" on the last page).
+// Reactions are summarized for userId, who may be empty.
+func (pr *CommentRepository) GetByPost(ctx context.Context, postId, userId string, filter dtocomment.CommentFilter) ([]dtocomment.Comment, string, error) {
+	after := time.Time{}
+	afterId := "00000000-0000-0000-0000-000000000000"
+	if filter.Cursor != "" {
+		var err error
+		after, afterId, err = cursor.DecodeTime(filter.Cursor)
+		if err != nil {
+			return []dtocomment.Comment{}, "", err
+		}
+	}
+
+	sql := `SELECT
+		comments.id,
+		comments.comment,
+		comments.created_at,
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at
+	FROM comments
+	JOIN users ON users.id = comments.user_id
+	WHERE comments.post_id = $1 AND (comments.created_at, comments.id) > ($2, $3)
+	ORDER BY comments.created_at, comments.id
+	LIMIT $4`
+	rows, err := db.Conn(ctx, pr.db).Query(ctx, sql, postId, after, afterId, filter.Limit+1)
+	if err != nil {
+		return []dtocomment.Comment{}, "", err
+	}
+
+	var (
+		lastId        string
+		lastCreatedAt time.Time
+		next          string
+	)
+	data := make([]dtocomment.Comment, 0)
+	for rows.Next() {
+		if int64(len(data)) == filter.Limit {
+			next = cursor.EncodeTime(lastCreatedAt, lastId)
+			break
+		}
+
+		comment, createdAt, err := scanComment(rows)
+		if err != nil {
+			rows.Close()
+			return []dtocomment.Comment{}, "", err
+		}
+
+		lastId, lastCreatedAt = comment.ID, createdAt
+		data = append(data, comment)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return []dtocomment.Comment{}, "", err
+	}
+
+	if err := pr.setDetails(ctx, data, userId); err != nil {
+		return []dtocomment.Comment{}, "", err
+	}
+
+	return data, next, nil
+}
+
+// GetFirstPages returns the first page of comments of every post in postIds,
+// the page GetByPost returns without a cursor, keyed by post id together with
+// the cursors of the second pages. Each post reads at most limit+1 comments.
+func (pr *CommentRepository) GetFirstPages(ctx context.Context, postIds []string, userId string, limit int64) (map[string][]dtocomment.Comment, map[string]string, error) {
+	sql := `SELECT
+		comments.post_id,
+		comments.id,
+		comments.comment,
+		comments.created_at,
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at
+	FROM unnest($1::uuid[]) AS posts (id)
+	CROSS JOIN LATERAL (
+		SELECT * FROM comments
+		WHERE comments.post_id = posts.id
+		ORDER BY comments.created_at, comments.id
+		LIMIT $2
+	) comments
+	JOIN users ON users.id = comments.user_id
+	ORDER BY comments.post_id, comments.created_at, comments.id`
+	rows, err := db.Conn(ctx, pr.db).Query(ctx, sql, postIds, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		all    []dtocomment.Comment
+		postOf []string
+		lastAt = make(map[string]time.Time)
+		lastId = make(map[string]string)
+		counts = make(map[string]int64)
+		next   = make(map[string]string)
+	)
+	for rows.Next() {
+		var postId string
+		comment, createdAt, err := scanComment(rows, &postId)
+		if err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+
+		if counts[postId] == limit {
+			next[postId] = cursor.EncodeTime(lastAt[postId], lastId[postId])
+			continue
+		}
+		counts[postId]++
+		lastAt[postId], lastId[postId] = createdAt, comment.ID
+		all = append(all, comment)
+		postOf = append(postOf, postId)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := pr.setDetails(ctx, all, userId); err != nil {
+		return nil, nil, err
+	}
+
+	data := make(map[string][]dtocomment.Comment, len(postIds))
+	for i, comment := range all {
+		data[postOf[i]] = append(data[postOf[i]], comment)
+	}
+
+	return data, next, nil
+}
+
+// scanComment reads a comment row selected with its creator, after any
+// leading columns given in dest, and returns it with its creation time.
+func scanComment(rows pgx.Rows, dest ...any) (dtocomment.Comment, time.Time, error) {
+	var (
+		comment          dtocomment.Comment
+		createdAt        time.Time
+		creatorCreatedAt time.Time
+	)
+	dest = append(dest,
+		&comment.ID,
+		&comment.Comment,
+		&createdAt,
+		&comment.Creator.ID,
+		&comment.Creator.Name,
+		&comment.Creator.ImageUrl,
+		&comment.Creator.FriendCount,
+		&creatorCreatedAt)
+	if err := rows.Scan(dest...); err != nil {
+		return comment, createdAt, err
+	}
+
+	comment.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+	comment.Creator.CreatedAt = creatorCreatedAt.Format("2006-01-02 15:04:05.999")
+	return comment, createdAt, nil
+}
+
+// setDetails loads the reactions, summarized for userId, and the mentions of
+// every comment in data, and renders the mentions as links.
+func (pr *CommentRepository) setDetails(ctx context.Context, data []dtocomment.Comment, userId string) error {
+	commentIds := make([]string, 0, len(data))
+	for _, comment := range data {
+		commentIds = append(commentIds, comment.ID)
+	}
+
+	reactions, err := getReactions(ctx, pr.db, entity.ReactionTargetComment, commentIds, userId)
+	if err != nil {
 		return err
 	}
 
+	mentions, err := getMentions(ctx, pr.db, entity.MentionTargetComment, commentIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range data {
+		commentReactions := reactions[data[i].ID]
+		data[i].Reactions = &commentReactions
+		data[i].Mentions = withMentions(mentions[data[i].ID])
+		data[i].CommentInHtml = mention.RenderText(data[i].Comment, mentionNames(mentions[data[i].ID]))
+	}
+
 	return nil
 }
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+// Counters lists every denormalized count column the reconciliation job
+// keeps in line with its source table. New count columns are registered here.
+var Counters = []entity.Counter{
+	{
+		Name:   "friend_count",
+		Table:  "users",
+		Column: "friend_count",
+		Source: `SELECT count(friend_id) FROM friends WHERE friends.user_id = users.id`,
+	},
+	{
+		Name:   "follower_count",
+		Table:  "users",
+		Column: "follower_count",
+		Source: `SELECT count(follower_id) FROM follows WHERE follows.following_id = users.id`,
+	},
+	{
+		Name:   "following_count",
+		Table:  "users",
+		Column: "following_count",
+		Source: `SELECT count(following_id) FROM follows WHERE follows.follower_id = users.id`,
+	},
+	{
+		Name:   "reshare_count",
+		Table:  "posts",
+		Column: "reshare_count",
+		Source: `SELECT count(reshares.id) FROM posts reshares WHERE reshares.reshare_of = posts.id AND reshares.deleted_at IS NULL`,
+	},
+	{
+		Name:   "tag_usage_count",
+		Table:  "tags",
+		Key:    "name",
+		Column: "usage_count",
+		Source: `SELECT count(DISTINCT posts.id) FROM posts WHERE posts.tags @> ARRAY[tags.name] AND posts.visibility = 'public' AND posts.deleted_at IS NULL`,
+	},
+}
+
+type CounterRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewCounterRepo(db *pgxpool.Pool, log *zap.Logger) *CounterRepository {
+	return &CounterRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Reconcile checks the next batch of rows after the given key and, unless
+// dryRun is set, overwrites the stored count of every row that drifted.
+func (cr *CounterRepository) Reconcile(ctx context.Context, counter entity.Counter, after string, limit int, dryRun bool) (entity.CounterBatch, error) {
+	var batch entity.CounterBatch
+	key := counter.Key
+	if key == "" {
+		key = "id"
+		if after == "" {
+			after = "00000000-0000-0000-0000-000000000000"
+		}
+	}
+
+	err := db.WithinTransaction(ctx, cr.db, func(ctx context.Context) error {
+		// the batch is locked before it is counted, so an increment that
+		// commits while the count runs waits and is applied on top of the
+		// corrected value instead of being overwritten by it
+		lock := ""
+		if !dryRun {
+			lock = "FOR UPDATE"
+		}
+		rows, err := db.Conn(ctx, cr.db).Query(ctx, fmt.Sprintf(`SELECT %[2]s::text FROM %[1]s
+		WHERE %[2]s > $1
+		ORDER BY %[2]s
+		LIMIT $2
+		%[3]s`, counter.Table, key, lock), after, limit)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, limit)
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		sql := fmt.Sprintf(`WITH batch AS (
+			SELECT %[1]s.%[4]s AS row_key, %[1]s.%[2]s AS stored, (%[3]s) AS actual
+			FROM %[1]s
+			WHERE %[1]s.%[4]s = ANY($1)
+		),
+		fixed AS (
+			UPDATE %[1]s SET %[2]s = batch.actual
+			FROM batch
+			WHERE %[1]s.%[4]s = batch.row_key AND batch.stored <> batch.actual AND NOT $2
+			RETURNING %[1]s.%[4]s
+		)
+		SELECT
+			COALESCE((SELECT row_key FROM batch ORDER BY row_key desc LIMIT 1)::text, ''),
+			(SELECT count(*) FROM batch),
+			(SELECT count(*) FROM batch WHERE stored <> actual),
+			(SELECT count(*) FROM fixed)`, counter.Table, counter.Column, counter.Source, key)
+
+		return db.Conn(ctx, cr.db).QueryRow(ctx, sql, ids, dryRun).Scan(
+			&batch.LastId,
+			&batch.Scanned,
+			&batch.Drifted,
+			&batch.Corrected)
+	})
+	if err != nil {
+		return entity.CounterBatch{}, err
+	}
+
+	return batch, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type DraftRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewDraftRepo(db *pgxpool.Pool, log *zap.Logger) *DraftRepository {
+	return &DraftRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Insert stores a new draft at version 1 and returns it as stored.
+func (dr *DraftRepository) Insert(ctx context.Context, data entity.Draft) (entity.Draft, error) {
+	sql := `INSERT INTO drafts (id, user_id, content, tags, visibility, attachments, version, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,1,now(),now())
+	RETURNING id, user_id, content, tags, visibility, attachments, version, created_at, updated_at`
+	return scanDraft(db.Conn(ctx, dr.db).QueryRow(ctx, sql, data.ID, data.UserId, data.PostInHtml, data.Tags, data.Visibility, data.Attachments))
+}
+
+// GetByUser returns the drafts of userId, the most recently saved first.
+func (dr *DraftRepository) GetByUser(ctx context.Context, userId string) ([]entity.Draft, error) {
+	sql := `SELECT id, user_id, content, tags, visibility, attachments, version, created_at, updated_at
+	FROM drafts WHERE user_id = $1
+	ORDER BY updated_at desc, id`
+	rows, err := db.Conn(ctx, dr.db).Query(ctx, sql, userId)
+	if err != nil {
+		return []entity.Draft{}, err
+	}
+	defer rows.Close()
+
+	data := make([]entity.Draft, 0)
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			return []entity.Draft{}, err
+		}
+		data = append(data, draft)
+	}
+
+	return data, rows.Err()
+}
+
+func (dr *DraftRepository) FindById(ctx context.Context, id string) (entity.Draft, error) {
+	sql := `SELECT id, user_id, content, tags, visibility, attachments, version, created_at, updated_at
+	FROM drafts WHERE id = $1`
+	return scanDraft(db.Conn(ctx, dr.db).QueryRow(ctx, sql, id))
+}
+
+// Update saves over the draft when it is still at data.Version and returns it
+// as stored, at its new version. It returns pgx.ErrNoRows when the draft was
+// saved, published or expired since that version was read.
+func (dr *DraftRepository) Update(ctx context.Context, data entity.Draft) (entity.Draft, error) {
+	sql := `UPDATE drafts SET content = $3, tags = $4, visibility = $5, attachments = $6, version = version + 1, updated_at = now()
+	WHERE id = $1 AND version = $2
+	RETURNING id, user_id, content, tags, visibility, attachments, version, created_at, updated_at`
+	return scanDraft(db.Conn(ctx, dr.db).QueryRow(ctx, sql, data.ID, data.Version, data.PostInHtml, data.Tags, data.Visibility, data.Attachments))
+}
+
+// Delete removes the draft when it is still at version, and returns
+// pgx.ErrNoRows otherwise.
+func (dr *DraftRepository) Delete(ctx context.Context, id string, version int64) error {
+	tag, err := db.Conn(ctx, dr.db).Exec(ctx, `DELETE FROM drafts WHERE id = $1 AND version = $2`, id, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Expire removes up to limit drafts last saved more than ttl ago and returns
+// how many were removed.
+func (dr *DraftRepository) Expire(ctx context.Context, ttl time.Duration, limit int) (int64, error) {
+	sql := `DELETE FROM drafts WHERE id IN (
+		SELECT id FROM drafts
+		WHERE updated_at < now() - make_interval(secs => $1)
+		LIMIT $2
+	)`
+	tag, err := db.Conn(ctx, dr.db).Exec(ctx, sql, ttl.Seconds(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanDraft(row pgx.Row) (entity.Draft, error) {
+	var draft entity.Draft
+	var createdAt, updatedAt time.Time
+	err := row.Scan(&draft.ID, &draft.UserId, &draft.PostInHtml, &draft.Tags, &draft.Visibility, &draft.Attachments, &draft.Version, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Draft{}, err
+	}
+
+	draft.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+	draft.UpdatedAt = updatedAt.Format("2006-01-02 15:04:05.999")
+	return draft, nil
+}
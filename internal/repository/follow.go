@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/follow"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type FollowRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewFollowRepo(db *pgxpool.Pool, log *zap.Logger) *FollowRepository {
+	return &FollowRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (fr *FollowRepository) FindByRelation(ctx context.Context, followerId, followingId string) (int, error) {
+	var count int
+	sql := `SELECT count(follower_id) FROM follows WHERE follower_id = $1 and following_id = $2`
+	if err := db.Conn(ctx, fr.db).QueryRow(ctx, sql, followerId, followingId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Insert follows and bumps both counters in one statement, so the counters
+// only move when a new row was actually written.
+func (fr *FollowRepository) Insert(ctx context.Context, followerId, followingId string) error {
+	sql := `WITH ins AS (
+		INSERT INTO follows (follower_id, following_id, created_at) VALUES ($1,$2,now())
+		ON CONFLICT DO NOTHING
+		RETURNING follower_id, following_id
+	)
+	UPDATE users SET
+		following_count = following_count + CASE WHEN users.id = ins.follower_id THEN 1 ELSE 0 END,
+		follower_count = follower_count + CASE WHEN users.id = ins.following_id THEN 1 ELSE 0 END
+	FROM ins
+	WHERE users.id = ins.follower_id or users.id = ins.following_id`
+	if _, err := db.Conn(ctx, fr.db).Exec(ctx, sql, followerId, followingId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (fr *FollowRepository) Delete(ctx context.Context, followerId, followingId string) error {
+	sql := `WITH del AS (
+		DELETE FROM follows WHERE follower_id = $1 and following_id = $2
+		RETURNING follower_id, following_id
+	)
+	UPDATE users SET
+		following_count = following_count - CASE WHEN users.id = del.follower_id THEN 1 ELSE 0 END,
+		follower_count = follower_count - CASE WHEN users.id = del.following_id THEN 1 ELSE 0 END
+	FROM del
+	WHERE users.id = del.follower_id or users.id = del.following_id`
+	if _, err := db.Conn(ctx, fr.db).Exec(ctx, sql, followerId, followingId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (fr *FollowRepository) GetFollowers(ctx context.Context, userId string, filter dto.FollowFilter) ([]entity.User, int64, error) {
+	sql := `SELECT
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at
+		FROM follows
+		JOIN users ON users.id = follows.follower_id
+		WHERE follows.following_id = $1
+		ORDER BY follows.created_at desc
+		LIMIT $2 OFFSET $3`
+
+	data, err := fr.scanUsers(ctx, sql, userId, filter)
+	if err != nil {
+		return []entity.User{}, 0, err
+	}
+
+	var total int64
+	if err := db.Conn(ctx, fr.db).QueryRow(ctx, `SELECT follower_count FROM users WHERE id = $1`, userId).Scan(&total); err != nil {
+		return []entity.User{}, 0, err
+	}
+
+	return data, total, nil
+}
+
+func (fr *FollowRepository) GetFollowing(ctx context.Context, userId string, filter dto.FollowFilter) ([]entity.User, int64, error) {
+	sql := `SELECT
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at
+		FROM follows
+		JOIN users ON users.id = follows.following_id
+		WHERE follows.follower_id = $1
+		ORDER BY follows.created_at desc
+		LIMIT $2 OFFSET $3`
+
+	data, err := fr.scanUsers(ctx, sql, userId, filter)
+	if err != nil {
+		return []entity.User{}, 0, err
+	}
+
+	var total int64
+	if err := db.Conn(ctx, fr.db).QueryRow(ctx, `SELECT following_count FROM users WHERE id = $1`, userId).Scan(&total); err != nil {
+		return []entity.User{}, 0, err
+	}
+
+	return data, total, nil
+}
+
+func (fr *FollowRepository) scanUsers(ctx context.Context, sql, userId string, filter dto.FollowFilter) ([]entity.User, error) {
+	rows, err := db.Conn(ctx, fr.db).Query(ctx, sql, userId, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	data := make([]entity.User, 0)
+	for rows.Next() {
+		var user entity.User
+		var createdAt time.Time
+		if err := rows.Scan(&user.ID, &user.Name, &user.ImageUrl, &user.FriendCount, &createdAt); err != nil {
+			return nil, err
+		}
+
+		user.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+		data = append(data, user)
+	}
+
+	return data, rows.Err()
+}
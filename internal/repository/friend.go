@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/friend"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
 	"go.uber.org/zap"
 )
 
@@ -23,7 +26,7 @@ func NewFriendRepo(db *pgxpool.Pool, log *zap.Logger) *FriendRepository {
 func (ur *FriendRepository) FindByRelation(ctx context.Context, userId, friendId string) (int, error) {
 	var count int
 	sql := `SELECT count(user_id) password FROM friends WHERE user_id = $1 and friend_id = $2`
-	err := ur.db.QueryRow(ctx, sql, userId, friendId).Scan(&count)
+	err := db.Conn(ctx, ur.db).QueryRow(ctx, sql, userId, friendId).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -31,34 +34,92 @@ func (ur *FriendRepository) FindByRelation(ctx context.Context, userId, friendId
 	return count, nil
 }
 
+// Insert stores both directions of the friendship. Rows that already exist
+// are skipped and friend_count is only bumped for the users whose row was
+// actually written, so retrying an insert never inflates the counters.
 func (ur *FriendRepository) Insert(ctx context.Context, userId, friendId string) error {
-	var sql string
+	return db.WithinTransaction(ctx, ur.db, func(ctx context.Context) error {
+		sql := `WITH ins AS (
+			INSERT INTO friends (user_id, friend_id, created_at) VALUES ($1,$2,now()),($2,$1,now())
+			ON CONFLICT (user_id, friend_id) DO NOTHING
+			RETURNING user_id
+		)
+		UPDATE users SET friend_count = friend_count + 1 FROM ins WHERE users.id = ins.user_id`
+		if _, err := db.Conn(ctx, ur.db).Exec(ctx, sql, userId, friendId); err != nil {
+			return err
+		}
 
-	var datetime = time.Now()
-	dt := datetime.Format(time.RFC3339)
+		return linkTimelines(ctx, ur.db, userId, friendId)
+	})
+}
 
-	sql = `INSERT INTO friends (user_id, friend_id, created_at) VALUES ($1,$2,$3),($4,$5,$6)`
-	if _, err := ur.db.Exec(ctx, sql, userId, friendId, dt, friendId, userId, dt); err != nil {
-		return err
-	}
+// Delete removes both directions of the friendship together with the list
+// memberships that depended on it. Deleting a pair that does not exist is a
+// no-op.
+func (ur *FriendRepository) Delete(ctx context.Context, userId, friendId string) error {
+	return db.WithinTransaction(ctx, ur.db, func(ctx context.Context) error {
+		sql := `WITH del AS (
+			DELETE FROM friends
+			WHERE (user_id = $2 and friend_id = $1) or (user_id = $1 and friend_id = $2)
+			RETURNING user_id
+		)
+		UPDATE users SET friend_count = friend_count - 1 FROM del WHERE users.id = del.user_id`
+		if _, err := db.Conn(ctx, ur.db).Exec(ctx, sql, userId, friendId); err != nil {
+			return err
+		}
 
-	userSql := `UPDATE users SET friend_count = friend_count + 1 WHERE (id = $1 or id = $2)`
-	if _, err := ur.db.Exec(ctx, userSql, userId, friendId); err != nil {
-		return err
-	}
+		listSql := `DELETE FROM friend_list_members
+		WHERE (friend_id = $2 AND list_id IN (SELECT id FROM friend_lists WHERE user_id = $1))
+		or (friend_id = $1 AND list_id IN (SELECT id FROM friend_lists WHERE user_id = $2))`
+		if _, err := db.Conn(ctx, ur.db).Exec(ctx, listSql, userId, friendId); err != nil {
+			return err
+		}
 
-	return nil
+		return unlinkTimelines(ctx, ur.db, userId, friendId)
+	})
 }
 
-func (ur *FriendRepository) Delete(ctx context.Context, userId, friendId string) error {
-	sql := `DELETE from friends where (user_id = $2 and friend_id = $1) or (user_id = $1 and friend_id = $2)`
-	if _, err := ur.db.Exec(ctx, sql, userId, friendId); err != nil {
-		return err
+// GetMutualFriends returns the page of users that are friends with both
+// userId and filter.UserId, plus the total number of mutual friends.
+func (ur *FriendRepository) GetMutualFriends(ctx context.Context, userId string, filter dto.MutualFriendFilter) ([]entity.User, int64, error) {
+	var total int64
+	countSql := `SELECT count(mine.friend_id)
+	FROM friends mine
+	JOIN friends theirs ON theirs.friend_id = mine.friend_id AND theirs.user_id = $2
+	WHERE mine.user_id = $1`
+	if err := db.Conn(ctx, ur.db).QueryRow(ctx, countSql, userId, filter.UserId).Scan(&total); err != nil {
+		return []entity.User{}, 0, err
 	}
-	userSql := `UPDATE users SET friend_count = friend_count - 1 WHERE (id = $1 or id = $2)`
-	if _, err := ur.db.Exec(ctx, userSql, userId, friendId); err != nil {
-		return err
+
+	sql := `SELECT
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at
+	FROM friends mine
+	JOIN friends theirs ON theirs.friend_id = mine.friend_id AND theirs.user_id = $2
+	JOIN users ON users.id = mine.friend_id
+	WHERE mine.user_id = $1
+	ORDER BY users.friend_count desc, users.id
+	LIMIT $3 OFFSET $4`
+	rows, err := db.Conn(ctx, ur.db).Query(ctx, sql, userId, filter.UserId, filter.Limit, filter.Offset)
+	if err != nil {
+		return []entity.User{}, 0, err
+	}
+	defer rows.Close()
+
+	data := make([]entity.User, 0)
+	for rows.Next() {
+		var user entity.User
+		var createdAt time.Time
+		if err := rows.Scan(&user.ID, &user.Name, &user.ImageUrl, &user.FriendCount, &createdAt); err != nil {
+			return []entity.User{}, 0, err
+		}
+
+		user.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+		data = append(data, user)
 	}
 
-	return nil
+	return data, total, rows.Err()
 }
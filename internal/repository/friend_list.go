@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type FriendListRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewFriendListRepo(db *pgxpool.Pool, log *zap.Logger) *FriendListRepository {
+	return &FriendListRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (lr *FriendListRepository) Insert(ctx context.Context, data entity.FriendList) error {
+	sql := `INSERT INTO friend_lists (id, user_id, name, created_at) VALUES ($1,$2,$3,now())`
+	if _, err := db.Conn(ctx, lr.db).Exec(ctx, sql, data.ID, data.UserId, data.Name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (lr *FriendListRepository) FindById(ctx context.Context, listId string) (entity.FriendList, error) {
+	var createdAt time.Time
+	list := entity.FriendList{}
+	sql := `SELECT
+		friend_lists.id,
+		friend_lists.user_id,
+		friend_lists.name,
+		(SELECT count(friend_id) FROM friend_list_members WHERE friend_list_members.list_id = friend_lists.id),
+		friend_lists.created_at
+	FROM friend_lists WHERE friend_lists.id = $1`
+	err := db.Conn(ctx, lr.db).QueryRow(ctx, sql, listId).Scan(&list.ID, &list.UserId, &list.Name, &list.MemberCount, &createdAt)
+	if err != nil {
+		return list, err
+	}
+
+	list.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+	return list, nil
+}
+
+func (lr *FriendListRepository) GetByUser(ctx context.Context, userId string) ([]entity.FriendList, error) {
+	sql := `SELECT
+		friend_lists.id,
+		friend_lists.user_id,
+		friend_lists.name,
+		(SELECT count(friend_id) FROM friend_list_members WHERE friend_list_members.list_id = friend_lists.id),
+		friend_lists.created_at
+	FROM friend_lists WHERE friend_lists.user_id = $1
+	ORDER BY friend_lists.created_at`
+	rows, err := db.Conn(ctx, lr.db).Query(ctx, sql, userId)
+	if err != nil {
+		return []entity.FriendList{}, err
+	}
+	defer rows.Close()
+
+	data := make([]entity.FriendList, 0)
+	for rows.Next() {
+		var list entity.FriendList
+		var createdAt time.Time
+		if err := rows.Scan(&list.ID, &list.UserId, &list.Name, &list.MemberCount, &createdAt); err != nil {
+			return []entity.FriendList{}, err
+		}
+
+		list.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+		data = append(data, list)
+	}
+
+	return data, rows.Err()
+}
+
+// Delete removes the list; its memberships go with it through ON DELETE CASCADE.
+func (lr *FriendListRepository) Delete(ctx context.Context, listId string) error {
+	if _, err := db.Conn(ctx, lr.db).Exec(ctx, `DELETE FROM friend_lists WHERE id = $1`, listId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (lr *FriendListRepository) NameCheck(ctx context.Context, userId, name string) (int64, error) {
+	var count int64
+	sql := `SELECT count(id) FROM friend_lists WHERE user_id = $1 AND name = $2`
+	if err := db.Conn(ctx, lr.db).QueryRow(ctx, sql, userId, name).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (lr *FriendListRepository) InsertMember(ctx context.Context, listId, friendId string) error {
+	sql := `INSERT INTO friend_list_members (list_id, friend_id, created_at) VALUES ($1,$2,now()) ON CONFLICT DO NOTHING`
+	if _, err := db.Conn(ctx, lr.db).Exec(ctx, sql, listId, friendId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (lr *FriendListRepository) DeleteMember(ctx context.Context, listId, friendId string) error {
+	sql := `DELETE FROM friend_list_members WHERE list_id = $1 AND friend_id = $2`
+	if _, err := db.Conn(ctx, lr.db).Exec(ctx, sql, listId, friendId); err != nil {
+		return err
+	}
+
+	return nil
+}
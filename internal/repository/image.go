@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type ImageRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewImageRepo(db *pgxpool.Pool, log *zap.Logger) *ImageRepository {
+	return &ImageRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (ir *ImageRepository) Insert(ctx context.Context, data entity.Image) error {
+	sql := `INSERT INTO images (id, user_id, url, created_at) VALUES ($1,$2,$3,now())`
+	if _, err := db.Conn(ctx, ir.db).Exec(ctx, sql, data.ID, data.UserId, data.ImageUrl); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindByUrls maps each of urls uploaded by userId to its image id. Urls that
+// were not uploaded by userId are left out.
+func (ir *ImageRepository) FindByUrls(ctx context.Context, userId string, urls []string) (map[string]string, error) {
+	sql := `SELECT id, url FROM images WHERE user_id = $1 AND url = ANY($2)`
+	rows, err := db.Conn(ctx, ir.db).Query(ctx, sql, userId, urls)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string, len(urls))
+	for rows.Next() {
+		var id, url string
+		if err := rows.Scan(&id, &url); err != nil {
+			return nil, err
+		}
+		ids[url] = id
+	}
+
+	return ids, rows.Err()
+}
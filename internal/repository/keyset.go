@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+)
+
+// keyset pages rows sorted by column and then by id, both in the same
+// direction, starting from a cursor position instead of an offset.
+type keyset struct {
+	column   string
+	idColumn string
+	desc     bool
+}
+
+// after is the condition selecting the rows past the cursor position held in
+// the key and id placeholders, in the direction the cursor reads.
+func (k keyset) after(page cursor.Page, key, id string) string {
+	op := ">"
+	if k.desc != (page.Direction == cursor.Prev) {
+		op = "<"
+	}
+
+	return fmt.Sprintf("(%s, %s) %s (%s, %s)", k.column, k.idColumn, op, key, id)
+}
+
+// order sorts the rows the way the page is read. Prev pages are read
+// backwards from the cursor, so their rows come out reversed.
+func (k keyset) order(page cursor.Page) string {
+	dir := "asc"
+	if k.desc != (page.Direction == cursor.Prev) {
+		dir = "desc"
+	}
+
+	return fmt.Sprintf("%[1]s %[3]s, %[2]s %[3]s", k.column, k.idColumn, dir)
+}
+
+// keysetCursors returns the cursors around a page of rows fetched with one row
+// more than limit, trimming that extra row and flipping rows read backwards
+// into display order. positions holds the sort key and id of each row. started
+// tells whether the page was reached from a cursor or an offset, which means
+// there are rows before it.
+func keysetCursors[T any](rows []T, positions []cursor.Page, limit int64, page cursor.Page, started bool) ([]T, string, string) {
+	more := int64(len(rows)) > limit
+	if more {
+		rows, positions = rows[:limit], positions[:limit]
+	}
+
+	if page.Direction == cursor.Prev {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+			positions[i], positions[j] = positions[j], positions[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, "", ""
+	}
+
+	var next, prev string
+	if more || page.Direction == cursor.Prev {
+		last := positions[len(positions)-1]
+		last.Direction = cursor.Next
+		next = cursor.EncodePage(last)
+	}
+	if (more && page.Direction == cursor.Prev) || (started && page.Direction != cursor.Prev) {
+		first := positions[0]
+		first.Direction = cursor.Prev
+		prev = cursor.EncodePage(first)
+	}
+
+	return rows, next, prev
+}
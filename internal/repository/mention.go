@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+)
+
+// insertMentions records the mentions of userIds in a post or comment of
+// postId. Users that do not exist or cannot see the post are skipped, so a
+// mention never exposes a post to someone outside its audience.
+func insertMentions(ctx context.Context, pool *pgxpool.Pool, postId, targetType, targetId string, userIds []string) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	sql := `INSERT INTO mentions (post_id, target_type, target_id, user_id, created_at)
+	SELECT posts.id, $2, $3, users.id, now()
+	FROM posts
+	JOIN users ON users.id = ANY($4)
+	WHERE posts.id = $1 AND ` + visibleTo("users.id") + `
+	ON CONFLICT DO NOTHING`
+	if _, err := db.Conn(ctx, pool).Exec(ctx, sql, postId, targetType, targetId, userIds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func deleteMentions(ctx context.Context, pool *pgxpool.Pool, targetType, targetId string) error {
+	sql := `DELETE FROM mentions WHERE target_type = $1 AND target_id = $2`
+	if _, err := db.Conn(ctx, pool).Exec(ctx, sql, targetType, targetId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getMentions loads the users mentioned in targetIds, keyed by target id.
+func getMentions(ctx context.Context, pool *pgxpool.Pool, targetType string, targetIds []string) (map[string][]entity.Mention, error) {
+	sql := `SELECT mentions.target_id, users.id, users.name
+	FROM mentions
+	JOIN users ON users.id = mentions.user_id
+	WHERE mentions.target_type = $1 AND mentions.target_id = ANY($2)
+	ORDER BY mentions.created_at, users.id`
+	rows, err := db.Conn(ctx, pool).Query(ctx, sql, targetType, targetIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	data := make(map[string][]entity.Mention)
+	for rows.Next() {
+		var targetId string
+		var mention entity.Mention
+		if err := rows.Scan(&targetId, &mention.UserId, &mention.Name); err != nil {
+			return nil, err
+		}
+		data[targetId] = append(data[targetId], mention)
+	}
+
+	return data, rows.Err()
+}
+
+func withMentions(mentions []entity.Mention) []entity.Mention {
+	if mentions == nil {
+		return []entity.Mention{}
+	}
+
+	return mentions
+}
+
+func mentionNames(mentions []entity.Mention) map[string]string {
+	names := make(map[string]string, len(mentions))
+	for _, mention := range mentions {
+		names[mention.UserId] = mention.Name
+	}
+
+	return names
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type PollRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewPollRepo(db *pgxpool.Pool, log *zap.Logger) *PollRepository {
+	return &PollRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// FindByPost returns the poll of a post as seen by userId.
+func (pr *PollRepository) FindByPost(ctx context.Context, postId, userId string) (*entity.Poll, error) {
+	polls, err := getPolls(ctx, pr.db, []string{postId}, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return polls[postId], nil
+}
+
+// Vote records the first vote of userId and reports false when userId has
+// voted already. It returns entity.ErrPollClosed once the poll has closed.
+func (pr *PollRepository) Vote(ctx context.Context, postId, userId string, optionIds []string) (bool, error) {
+	sql := `WITH poll AS (
+		SELECT closes_at IS NULL OR closes_at > now() AS open FROM polls WHERE post_id = $1
+	),
+	voted AS (
+		INSERT INTO poll_votes (post_id, user_id, option_ids, created_at)
+		SELECT $1, $2, $3, now() FROM poll WHERE poll.open
+		ON CONFLICT (post_id, user_id) DO NOTHING
+		RETURNING 1
+	)
+	SELECT COALESCE((SELECT open FROM poll), false), EXISTS (SELECT 1 FROM voted)`
+	return pr.recordVote(ctx, sql, postId, userId, optionIds)
+}
+
+// ChangeVote replaces the vote of userId and reports false when userId has
+// not voted yet. It returns entity.ErrPollClosed once the poll has closed.
+func (pr *PollRepository) ChangeVote(ctx context.Context, postId, userId string, optionIds []string) (bool, error) {
+	sql := `WITH poll AS (
+		SELECT closes_at IS NULL OR closes_at > now() AS open FROM polls WHERE post_id = $1
+	),
+	voted AS (
+		UPDATE poll_votes SET option_ids = $3, created_at = now()
+		FROM poll
+		WHERE poll_votes.post_id = $1 AND poll_votes.user_id = $2 AND poll.open
+		RETURNING 1
+	)
+	SELECT COALESCE((SELECT open FROM poll), false), EXISTS (SELECT 1 FROM voted)`
+	return pr.recordVote(ctx, sql, postId, userId, optionIds)
+}
+
+// recordVote runs a vote statement, which checks that the poll is still open
+// in the same statement it writes the vote in.
+func (pr *PollRepository) recordVote(ctx context.Context, sql, postId, userId string, optionIds []string) (bool, error) {
+	var open, recorded bool
+	if err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, postId, userId, optionIds).Scan(&open, &recorded); err != nil {
+		return false, err
+	}
+	if !open {
+		return false, entity.ErrPollClosed
+	}
+
+	return recorded, nil
+}
+
+// insertPoll stores the poll of a new post, giving its options their order.
+func insertPoll(ctx context.Context, pool *pgxpool.Pool, postId string, poll *entity.Poll) error {
+	var closesAt *time.Time
+	if poll.ClosesAt != "" {
+		t, err := time.Parse(time.RFC3339, poll.ClosesAt)
+		if err != nil {
+			return err
+		}
+		closesAt = &t
+	}
+
+	sql := `INSERT INTO polls (post_id, multiple, hide_results, closes_at) VALUES ($1,$2,$3,$4)`
+	if _, err := db.Conn(ctx, pool).Exec(ctx, sql, postId, poll.Multiple, poll.HideResults, closesAt); err != nil {
+		return err
+	}
+
+	for i, option := range poll.Options {
+		sql := `INSERT INTO poll_options (id, post_id, label, position) VALUES ($1,$2,$3,$4)`
+		if _, err := db.Conn(ctx, pool).Exec(ctx, sql, option.ID, postId, option.Label, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPolls loads the polls of postIds as seen by userId, keyed by post id.
+// Results are hidden from a viewer who has not voted on a poll with hidden
+// results, unless the viewer wrote the post or the poll is closed.
+func getPolls(ctx context.Context, pool *pgxpool.Pool, postIds []string, userId string) (map[string]*entity.Poll, error) {
+	polls := make(map[string]*entity.Poll)
+	if len(postIds) == 0 {
+		return polls, nil
+	}
+
+	authors := make(map[string]string)
+	rows, err := db.Conn(ctx, pool).Query(ctx, `SELECT polls.post_id, posts.user_id, polls.multiple, polls.hide_results, polls.closes_at, polls.closes_at <= now()
+	FROM polls
+	JOIN posts ON posts.id = polls.post_id
+	WHERE polls.post_id = ANY($1)`, postIds)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var (
+			postId, authorId string
+			closesAt         *time.Time
+			closed           *bool
+		)
+		poll := &entity.Poll{Options: []entity.PollOption{}, MyVotes: []string{}}
+		if err := rows.Scan(&postId, &authorId, &poll.Multiple, &poll.HideResults, &closesAt, &closed); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if closesAt != nil {
+			poll.ClosesAt = closesAt.UTC().Format(time.RFC3339)
+			poll.Closed = *closed
+		}
+		polls[postId] = poll
+		authors[postId] = authorId
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(polls) == 0 {
+		return polls, nil
+	}
+
+	pollIds := make([]string, 0, len(polls))
+	for postId := range polls {
+		pollIds = append(pollIds, postId)
+	}
+
+	rows, err = db.Conn(ctx, pool).Query(ctx, `SELECT poll_options.post_id, poll_options.id, poll_options.label,
+		(SELECT count(*) FROM poll_votes WHERE poll_votes.post_id = poll_options.post_id AND poll_options.id = ANY(poll_votes.option_ids))
+	FROM poll_options
+	WHERE poll_options.post_id = ANY($1)
+	ORDER BY poll_options.position`, pollIds)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var postId string
+		var votes int64
+		var option entity.PollOption
+		if err := rows.Scan(&postId, &option.ID, &option.Label, &votes); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		option.Votes = &votes
+		polls[postId].Options = append(polls[postId].Options, option)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	voters := make(map[string]int64)
+	rows, err = db.Conn(ctx, pool).Query(ctx, `SELECT post_id, count(*) FROM poll_votes WHERE post_id = ANY($1) GROUP BY post_id`, pollIds)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var postId string
+		var count int64
+		if err := rows.Scan(&postId, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		voters[postId] = count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if userId != "" {
+		rows, err = db.Conn(ctx, pool).Query(ctx, `SELECT post_id, option_ids FROM poll_votes WHERE user_id = $1 AND post_id = ANY($2)`, userId, pollIds)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var postId string
+			var optionIds []string
+			if err := rows.Scan(&postId, &optionIds); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			polls[postId].MyVotes = optionIds
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for postId, poll := range polls {
+		if poll.HideResults && !poll.Closed && len(poll.MyVotes) == 0 && authors[postId] != userId {
+			poll.ResultsHidden = true
+			for i := range poll.Options {
+				poll.Options[i].Votes = nil
+			}
+			continue
+		}
+
+		total := voters[postId]
+		poll.TotalVoters = &total
+	}
+
+	return polls, nil
+}
@@ -2,17 +2,21 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
+	"sort"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/mention"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/rank"
 	dtocomment "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/comment"
+	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
 	dtopost "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/post"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
 	"go.uber.org/zap"
 )
 
@@ -28,119 +32,976 @@ func NewPostRepo(db *pgxpool.Pool, log *zap.Logger) *PostRepository {
 	}
 }
 
-func (pr *PostRepository) Insert(ctx context.Context, data entity.Post, userId string) error {
-	sql := `INSERT INTO posts (id, user_id, content, tags) VALUES ($1,$2,$3,$4)`
-	if _, err := pr.db.Exec(ctx, sql, data.ID, userId, data.PostInHtml, data.Tags); err != nil {
-		return err
+// Insert stores a new post and puts it on the home timelines. Authors with
+// more than fanoutLimit friends are not fanned out; their friends pick the
+// post up when reading the feed instead.
+func (pr *PostRepository) Insert(ctx context.Context, data entity.Post, userId string, fanoutLimit int) error {
+	return db.WithinTransaction(ctx, pr.db, func(ctx context.Context) error {
+		sql := `INSERT INTO posts (id, user_id, content, tags, visibility, reshare_of, fanned_out)
+		VALUES ($1,$2,$3,$4,$5,$6, COALESCE((SELECT friend_count <= $7 FROM users WHERE id = $2), true))`
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, data.ID, userId, data.PostInHtml, data.Tags, data.Visibility, nullString(data.ReshareOf), fanoutLimit); err != nil {
+			return err
+		}
+
+		if err := fanOut(ctx, pr.db, data.ID); err != nil {
+			return err
+		}
+
+		if data.ReshareOf != "" {
+			sql := `UPDATE posts SET reshare_count = reshare_count + 1 WHERE id = $1`
+			if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, data.ReshareOf); err != nil {
+				return err
+			}
+		}
+
+		if err := insertMentions(ctx, pr.db, data.ID, entity.MentionTargetPost, data.ID, mentionIds(data.Mentions)); err != nil {
+			return err
+		}
+
+		if err := countTags(ctx, pr.db, data.ID, 1); err != nil {
+			return err
+		}
+
+		if data.Poll != nil {
+			if err := insertPoll(ctx, pr.db, data.ID, data.Poll); err != nil {
+				return err
+			}
+		}
+
+		for _, attachment := range data.Attachments {
+			sql := `INSERT INTO post_attachments (post_id, image_id, alt_text, position) VALUES ($1,$2,$3,$4)`
+			if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, data.ID, attachment.ImageId, attachment.AltText, attachment.Position); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// getAttachments loads the attachments of postIds in display order, keyed by
+// post id.
+func (pr *PostRepository) getAttachments(ctx context.Context, postIds []string) (map[string][]entity.Attachment, error) {
+	sql := `SELECT post_attachments.post_id, images.id, images.url, post_attachments.alt_text, post_attachments.position
+	FROM post_attachments
+	JOIN images ON images.id = post_attachments.image_id
+	WHERE post_attachments.post_id = ANY($1)
+	ORDER BY post_attachments.position`
+	rows, err := db.Conn(ctx, pr.db).Query(ctx, sql, postIds)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return nil
+	data := make(map[string][]entity.Attachment)
+	for rows.Next() {
+		var postId string
+		var attachment entity.Attachment
+		if err := rows.Scan(&postId, &attachment.ImageId, &attachment.ImageUrl, &attachment.AltText, &attachment.Position); err != nil {
+			return nil, err
+		}
+		data[postId] = append(data[postId], attachment)
+	}
+
+	return data, rows.Err()
 }
 
 func (pr *PostRepository) FindById(ctx context.Context, postId string) (entity.Post, error) {
 
 	var createdAt time.Time
+	var editedAt *time.Time
+	var reshareOf *string
 	post := entity.Post{}
-	sql := `SELECT id, user_id, content, tags, created_at FROM posts WHERE posts.id = $1`
-	if err := pr.db.QueryRow(ctx, sql, postId).Scan(&post.ID, &post.UserId, &post.PostInHtml, &post.Tags, &createdAt); err != nil {
+	sql := `SELECT id, user_id, content, tags, visibility, reshare_of, reshare_count, edited_at, created_at FROM posts WHERE posts.id = $1 AND posts.deleted_at IS NULL`
+	err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, postId).Scan(
+		&post.ID, &post.UserId, &post.PostInHtml, &post.Tags, &post.Visibility, &reshareOf, &post.ReshareCount, &editedAt, &createdAt)
+	if err != nil {
 		return post, err
 	}
 
 	post.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+	setEditedAt(&post, editedAt)
+	if reshareOf != nil {
+		post.ReshareOf = *reshareOf
+	}
 	return post, nil
 }
 
-func (pr *PostRepository) GetPostWithFilter(ctx context.Context, filter dtopost.PostFilter, userId string) ([]dtopost.Post, int64, error) {
+// GetPostWithFilter returns a page of the feed, newest first. Pages are read
+// from filter.Cursor when set, otherwise from filter.Offset.
+func (pr *PostRepository) GetPostWithFilter(ctx context.Context, filter dtopost.PostFilter, userId string) ([]dtopost.Post, metadto.Meta, error) {
+	page := cursor.Page{Direction: cursor.Next}
+	var after time.Time
+	if filter.Cursor != "" {
+		var err error
+		page, err = cursor.DecodePage(filter.Cursor)
+		if err != nil {
+			return []dtopost.Post{}, metadto.Meta{}, err
+		}
+		after, err = time.Parse(time.RFC3339Nano, page.Key)
+		if err != nil {
+			return []dtopost.Post{}, metadto.Meta{}, cursor.ErrInvalid
+		}
+	}
+
+	args := make([]any, 0)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// anonymous callers get the public feed, everyone else the posts of their
+	// friends (and followed users in the following feed) they are allowed to see
+	cond := visibleTo("")
+	var sources []timelineSource
+	if filter.Tag != "" {
+		visible, tagArgs := visibility(userId, args)
+		args = tagArgs
+		cond = visible + " AND posts.tags @> " + arg([]string{filter.Tag})
+	} else if userId != "" {
+		viewer := arg(userId)
+		if filter.Feed == "mentions" {
+			// posts mentioning the viewer in their content or in a comment
+			cond = fmt.Sprintf(`EXISTS (SELECT 1 FROM mentions WHERE mentions.post_id = posts.id AND mentions.user_id = %s) AND `, viewer) + visibleTo(viewer)
+		} else {
+			sources = timelineSources(viewer, filter.Feed)
+			cond = visibleTo(viewer)
+		}
+	}
 
-	where := fmt.Sprintf("WHERE (friends.friend_id = '%s' or posts.user_id = '%s')", userId, userId)
 	if filter.Search != "" {
-		where += " AND posts.content LIKE '%" + filter.Search + "%'"
+		cond += " AND posts.content LIKE " + arg("%"+filter.Search+"%")
 	}
 
 	if len(filter.SearchTag) > 0 {
-		jsonTag, err := json.Marshal([]string(filter.SearchTag))
-		if err == nil {
-			replacer := strings.NewReplacer("[", "{", "]", "}")
-			stringTag := replacer.Replace(string(jsonTag))
-			where += fmt.Sprintf(" AND posts.tags && '%s'", stringTag)
+		cond += " AND posts.tags && " + arg(filter.SearchTag)
+	}
+
+	var (
+		total     int64
+		estimated bool
+	)
+	if filter.CountLimit > 0 {
+		countSql, countArgs := `SELECT 1 FROM posts JOIN users ON posts.user_id = users.id WHERE `+cond, args
+		if sources != nil {
+			countSql, countArgs = timelineQuery(sources, cond, args, cursor.Page{}, time.Time{}, filter.CountLimit+1)
 		}
+
+		var err error
+		total, estimated, err = countUpTo(ctx, pr.db, countSql, countArgs, filter.CountLimit)
+		if err != nil {
+			return []dtopost.Post{}, metadto.Meta{}, err
+		}
+	}
+
+	where := "WHERE " + cond
+	if sources != nil {
+		// the feed of a signed-in viewer is read from the top of each of its
+		// sources first, the page is then taken from the merged posts
+		limit, from := filter.Offset+filter.Limit+1, cursor.Page{}
+		if filter.Sort == "ranked" {
+			limit = filter.RankCandidates
+		} else if filter.Cursor != "" {
+			limit, from = filter.Limit+1, page
+		}
+
+		sql, timelineArgs := timelineQuery(sources, cond, args, from, after, limit)
+		ids, err := timelineIds(ctx, pr.db, sql, timelineArgs)
+		if err != nil {
+			return []dtopost.Post{}, metadto.Meta{}, err
+		}
+		where = "WHERE posts.id = ANY(" + arg(ids) + ") AND " + cond
+	}
+
+	feed := keyset{column: "posts.created_at", idColumn: "posts.id", desc: true}
+	offset := filter.Offset
+	var ranked []string
+	if filter.Sort == "ranked" {
+		var err error
+		ranked, err = pr.rankedPage(ctx, where, args, userId, filter)
+		if err != nil {
+			return []dtopost.Post{}, metadto.Meta{}, err
+		}
+		where += " AND posts.id = ANY(" + arg(ranked) + ")"
+		// only the scored posts can be paged through
+		if total > filter.RankCandidates {
+			total, estimated = filter.RankCandidates, false
+		}
+	} else if filter.Cursor != "" {
+		where += " AND " + feed.after(page, arg(after), arg(page.Id))
+		offset = 0
 	}
 
 	sql := fmt.Sprintf(`SELECT 
-	distinct(posts.id), 
+	posts.id, 
 	posts.content,
 	posts.tags, 
+	posts.visibility,
+	posts.reshare_of,
+	posts.reshare_count,
+	posts.edited_at,
 	posts.created_at, 
 	users.id, 
 	users.name,
 	users.image_url, 
 	users.friend_count, 
-	users.created_at,
-	array(SELECT (comments.comment || ',' || comments.created_at || ',' || users.id || ','  || users.name || ','  || users.image_url || ','  || users.friend_count || ','  || users.created_at) FROM comments JOIN users ON comments.user_id = users.id WHERE posts.id = comments.post_id) as comments
-	FROM posts 
+	users.created_at
+	FROM posts
 	JOIN users ON posts.user_id = users.id
-	LEFT JOIN friends ON posts.user_id = friends.user_id
 	%s 
-	ORDER BY posts.created_at desc 
-	LIMIT %d OFFSET %d`, where, filter.Limit, filter.Offset)
+	ORDER BY %s
+	LIMIT %d`, where, feed.order(page), filter.Limit+1)
+	if filter.Sort != "ranked" {
+		sql += fmt.Sprintf(" OFFSET %d", offset)
+	}
 
-	rows, err := pr.db.Query(ctx, sql)
+	rows, err := db.Conn(ctx, pr.db).Query(ctx, sql, args...)
 	if err != nil {
-		return []dtopost.Post{}, 0, err
+		return []dtopost.Post{}, metadto.Meta{}, err
 	}
 
 	data := make([]dtopost.Post, 0)
-	var count int64 = 0
+	positions := make([]cursor.Page, 0)
 	var createdAt time.Time
 	var creatorCreatedAt time.Time
 	for rows.Next() {
+		var editedAt *time.Time
+		var reshareOf *string
 		var post entity.Post
 		var creator entity.User
-		var commentString []string
-		// m := pgtype.NewMap()
 		err := rows.Scan(
 			&post.ID,
 			&post.PostInHtml,
 			&post.Tags,
+			&post.Visibility,
+			&reshareOf,
+			&post.ReshareCount,
+			&editedAt,
 			&createdAt,
 			&creator.ID,
 			&creator.Name,
 			&creator.ImageUrl,
 			&creator.FriendCount,
-			&creatorCreatedAt,
-			&commentString)
+			&creatorCreatedAt)
 		if err != nil {
-			return []dtopost.Post{}, 0, err
+			return []dtopost.Post{}, metadto.Meta{}, err
 		}
 
 		post.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
 		creator.CreatedAt = creatorCreatedAt.Format("2006-01-02 15:04:05.999")
-
-		comments := make([]dtocomment.Comment, 0)
-		for i := 0; i < len(commentString); i++ {
-			var comment dtocomment.Comment
-			commentArray := strings.Split(commentString[i], ",")
-
-			comment.Comment = commentArray[0]
-			comment.CreatedAt = commentArray[1]
-			comment.Creator.ID = commentArray[2]
-			comment.Creator.Name = commentArray[3]
-			comment.Creator.ImageUrl = commentArray[4]
-			comment.Creator.FriendCount, _ = strconv.ParseInt(commentArray[5], 10, 64)
-			comment.Creator.CreatedAt = commentArray[6]
-
-			comments = append(comments, comment)
+		setEditedAt(&post, editedAt)
+		if reshareOf != nil {
+			post.ReshareOf = *reshareOf
 		}
 
 		data = append(data, dtopost.Post{
 			ID:       post.ID,
-			Comments: comments,
+			Comments: []dtocomment.Comment{},
 			Post:     post,
 			Creator:  creator,
 		})
-		count += 1
+		positions = append(positions, cursor.Page{Key: createdAt.Format(time.RFC3339Nano), Id: post.ID})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return []dtopost.Post{}, metadto.Meta{}, err
+	}
+
+	var next, prev string
+	if filter.Sort == "ranked" {
+		// ranked pages are read by offset only, in the order the ids were ranked
+		order := make(map[string]int, len(ranked))
+		for i, id := range ranked {
+			order[id] = i
+		}
+		sort.Slice(data, func(i, j int) bool {
+			return order[data[i].ID] < order[data[j].ID]
+		})
+	} else {
+		data, next, prev = keysetCursors(data, positions, filter.Limit, page, filter.Cursor != "" || offset > 0)
+	}
+
+	postIds := make([]string, 0, len(data))
+	for _, post := range data {
+		postIds = append(postIds, post.ID)
+	}
+
+	attachments, err := pr.getAttachments(ctx, postIds)
+	if err != nil {
+		return []dtopost.Post{}, metadto.Meta{}, err
+	}
+
+	reactions, err := getReactions(ctx, pr.db, entity.ReactionTargetPost, postIds, userId)
+	if err != nil {
+		return []dtopost.Post{}, metadto.Meta{}, err
+	}
+
+	mentions, err := getMentions(ctx, pr.db, entity.MentionTargetPost, postIds)
+	if err != nil {
+		return []dtopost.Post{}, metadto.Meta{}, err
+	}
+
+	polls, err := getPolls(ctx, pr.db, postIds, userId)
+	if err != nil {
+		return []dtopost.Post{}, metadto.Meta{}, err
+	}
+
+	for i := range data {
+		data[i].Post.Attachments = withAttachments(attachments[data[i].ID])
+		data[i].Post.Reactions = reactions[data[i].ID]
+		data[i].Post.Poll = polls[data[i].ID]
+		setMentions(&data[i].Post, mentions[data[i].ID])
+	}
+
+	if err := pr.setReshares(ctx, data, userId); err != nil {
+		return []dtopost.Post{}, metadto.Meta{}, err
+	}
+
+	return data, metadto.Meta{
+		Limit:          filter.Limit,
+		Offset:         offset,
+		Total:          total,
+		TotalEstimated: estimated,
+		NextCursor:     next,
+		PrevCursor:     prev,
+	}, nil
+}
+
+// rankedPage scores the newest posts matching where, at most
+// filter.RankCandidates of them, and returns the ids on the requested page in
+// ranked order. Affinity is counted from userId's comments and reactions on
+// each author's posts.
+func (pr *PostRepository) rankedPage(ctx context.Context, where string, args []any, userId string, filter dtopost.PostFilter) ([]string, error) {
+	sql := fmt.Sprintf(`SELECT
+		posts.id,
+		posts.user_id,
+		posts.created_at,
+		(SELECT count(*) FROM comments WHERE comments.post_id = posts.id)
+	FROM posts
+	JOIN users ON posts.user_id = users.id
+	%s
+	ORDER BY posts.created_at desc, posts.id desc
+	LIMIT %d`, where, filter.RankCandidates)
+
+	rows, err := db.Conn(ctx, pr.db).Query(ctx, sql, args...)
+	if err != nil {
+		return []string{}, err
+	}
+
+	candidates := make([]rank.Candidate, 0)
+	authors := make(map[string]string)
+	for rows.Next() {
+		var candidate rank.Candidate
+		var authorId string
+		if err := rows.Scan(&candidate.ID, &authorId, &candidate.CreatedAt, &candidate.Comments); err != nil {
+			rows.Close()
+			return []string{}, err
+		}
+
+		candidates = append(candidates, candidate)
+		authors[candidate.ID] = authorId
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return []string{}, err
+	}
+
+	if userId != "" && len(candidates) > 0 {
+		authorIds := make([]string, 0, len(authors))
+		for _, authorId := range authors {
+			authorIds = append(authorIds, authorId)
+		}
+
+		affinity := make(map[string]int64)
+		rows, err := db.Conn(ctx, pr.db).Query(ctx, `SELECT posts.user_id, count(*)
+		FROM (
+			SELECT comments.post_id FROM comments WHERE comments.user_id = $1
+			UNION ALL
+			SELECT reactions.target_id FROM reactions WHERE reactions.user_id = $1 AND reactions.target_type = 'post'
+		) interactions (post_id)
+		JOIN posts ON posts.id = interactions.post_id
+		WHERE posts.user_id = ANY($2::uuid[]) AND posts.user_id <> $1
+		GROUP BY posts.user_id`, userId, authorIds)
+		if err != nil {
+			return []string{}, err
+		}
+		for rows.Next() {
+			var authorId string
+			var count int64
+			if err := rows.Scan(&authorId, &count); err != nil {
+				rows.Close()
+				return []string{}, err
+			}
+			affinity[authorId] = count
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return []string{}, err
+		}
+
+		for i := range candidates {
+			candidates[i].Affinity = affinity[authors[candidates[i].ID]]
+		}
+	}
+
+	rank.Sort(candidates, time.Now(), filter.Ranking)
+
+	ids := make([]string, 0, filter.Limit)
+	for i := filter.Offset; i < int64(len(candidates)) && i < filter.Offset+filter.Limit; i++ {
+		ids = append(ids, candidates[i].ID)
+	}
+
+	return ids, nil
+}
+
+func withAttachments(attachments []entity.Attachment) []entity.Attachment {
+	if attachments == nil {
+		return []entity.Attachment{}
+	}
+
+	return attachments
+}
+
+// visibleTo is the post visibility rule in SQL. Deleted posts are hidden from
+// everyone. Public posts can be seen by anyone, friends posts by the author's
+// friends, friends-of-friends posts additionally by friends of those friends
+// and only-me posts by the author alone. viewer is the placeholder holding the
+// viewing user's id; an empty viewer stands for an anonymous caller.
+func visibleTo(viewer string) string {
+	if viewer == "" {
+		return `(posts.deleted_at IS NULL AND posts.visibility = 'public')`
+	}
+
+	return fmt.Sprintf(`(posts.deleted_at IS NULL AND (
+		posts.user_id = %[1]s
+		or posts.visibility = 'public'
+		or (posts.visibility IN ('friends', 'friends-of-friends') AND EXISTS (
+			SELECT 1 FROM friends WHERE friends.user_id = posts.user_id AND friends.friend_id = %[1]s))
+		or (posts.visibility = 'friends-of-friends' AND EXISTS (
+			SELECT 1 FROM friends author_friends
+			JOIN friends second ON second.user_id = author_friends.friend_id
+			WHERE author_friends.user_id = posts.user_id AND second.friend_id = %[1]s))
+	))`, viewer)
+}
+
+// visibility appends userId to args when the caller is signed in and returns
+// the matching visibleTo condition together with the new args.
+func visibility(userId string, args []any) (string, []any) {
+	if userId == "" {
+		return visibleTo(""), args
+	}
+
+	args = append(args, userId)
+	return visibleTo(fmt.Sprintf("$%d", len(args))), args
+}
+
+// CountByTag counts the posts with tag that userId can see.
+func (pr *PostRepository) CountByTag(ctx context.Context, tag, userId string) (int64, error) {
+	var count int64
+	where, args := visibility(userId, []any{[]string{tag}})
+	sql := `SELECT count(posts.id) FROM posts WHERE posts.tags @> $1 AND ` + where
+	if err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (pr *PostRepository) IsVisible(ctx context.Context, postId, userId string) (bool, error) {
+	var visible bool
+	where, args := visibility(userId, []any{postId})
+	sql := `SELECT EXISTS (SELECT 1 FROM posts WHERE posts.id = $1 AND ` + where + `)`
+	if err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, args...).Scan(&visible); err != nil {
+		return false, err
+	}
+
+	return visible, nil
+}
+
+// GetPostById returns a single post if userId may see it. Posts that do not
+// exist and posts hidden from userId both return pgx.ErrNoRows.
+func (pr *PostRepository) GetPostById(ctx context.Context, postId, userId string) (dtopost.Post, error) {
+	var (
+		post             entity.Post
+		creator          entity.User
+		createdAt        time.Time
+		editedAt         *time.Time
+		reshareOf        *string
+		creatorCreatedAt time.Time
+	)
+
+	where, args := visibility(userId, []any{postId})
+	sql := `SELECT
+		posts.id,
+		posts.content,
+		posts.tags,
+		posts.visibility,
+		posts.reshare_of,
+		posts.reshare_count,
+		posts.edited_at,
+		posts.created_at,
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at
+	FROM posts
+	JOIN users ON posts.user_id = users.id
+	WHERE posts.id = $1 AND ` + where
+	err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, args...).Scan(
+		&post.ID,
+		&post.PostInHtml,
+		&post.Tags,
+		&post.Visibility,
+		&reshareOf,
+		&post.ReshareCount,
+		&editedAt,
+		&createdAt,
+		&creator.ID,
+		&creator.Name,
+		&creator.ImageUrl,
+		&creator.FriendCount,
+		&creatorCreatedAt)
+	if err != nil {
+		return dtopost.Post{}, err
+	}
+	if reshareOf != nil {
+		post.ReshareOf = *reshareOf
+	}
+
+	post.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+	creator.CreatedAt = creatorCreatedAt.Format("2006-01-02 15:04:05.999")
+	setEditedAt(&post, editedAt)
+
+	attachments, err := pr.getAttachments(ctx, []string{post.ID})
+	if err != nil {
+		return dtopost.Post{}, err
+	}
+	post.Attachments = withAttachments(attachments[post.ID])
+
+	reactions, err := getReactions(ctx, pr.db, entity.ReactionTargetPost, []string{post.ID}, userId)
+	if err != nil {
+		return dtopost.Post{}, err
+	}
+	post.Reactions = reactions[post.ID]
+
+	mentions, err := getMentions(ctx, pr.db, entity.MentionTargetPost, []string{post.ID})
+	if err != nil {
+		return dtopost.Post{}, err
+	}
+	setMentions(&post, mentions[post.ID])
+
+	polls, err := getPolls(ctx, pr.db, []string{post.ID}, userId)
+	if err != nil {
+		return dtopost.Post{}, err
+	}
+	post.Poll = polls[post.ID]
+
+	data := []dtopost.Post{{
+		ID:       post.ID,
+		Post:     post,
+		Comments: []dtocomment.Comment{},
+		Creator:  creator,
+	}}
+	if err := pr.setReshares(ctx, data, userId); err != nil {
+		return dtopost.Post{}, err
+	}
+
+	return data[0], nil
+}
+
+// setReshares embeds the original of every reshare in data. Originals that
+// are deleted or not visible to userId become tombstones.
+func (pr *PostRepository) setReshares(ctx context.Context, data []dtopost.Post, userId string) error {
+	originalIds := make([]string, 0)
+	for _, post := range data {
+		if post.Post.ReshareOf != "" {
+			originalIds = append(originalIds, post.Post.ReshareOf)
+		}
+	}
+	if len(originalIds) == 0 {
+		return nil
+	}
+
+	where, args := visibility(userId, []any{originalIds})
+	sql := `SELECT
+		posts.id,
+		posts.content,
+		posts.tags,
+		posts.visibility,
+		posts.reshare_count,
+		posts.edited_at,
+		posts.created_at,
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at
+	FROM posts
+	JOIN users ON posts.user_id = users.id
+	WHERE posts.id = ANY($1) AND ` + where
+	rows, err := db.Conn(ctx, pr.db).Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	originals := make(map[string]dtopost.Reshare)
+	for rows.Next() {
+		var (
+			post             entity.Post
+			creator          entity.User
+			createdAt        time.Time
+			editedAt         *time.Time
+			creatorCreatedAt time.Time
+		)
+		err := rows.Scan(
+			&post.ID,
+			&post.PostInHtml,
+			&post.Tags,
+			&post.Visibility,
+			&post.ReshareCount,
+			&editedAt,
+			&createdAt,
+			&creator.ID,
+			&creator.Name,
+			&creator.ImageUrl,
+			&creator.FriendCount,
+			&creatorCreatedAt)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		post.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+		creator.CreatedAt = creatorCreatedAt.Format("2006-01-02 15:04:05.999")
+		setEditedAt(&post, editedAt)
+		originals[post.ID] = dtopost.Reshare{ID: post.ID, Post: &post, Creator: &creator}
 	}
 	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	visibleIds := make([]string, 0, len(originals))
+	for id := range originals {
+		visibleIds = append(visibleIds, id)
+	}
+
+	attachments, err := pr.getAttachments(ctx, visibleIds)
+	if err != nil {
+		return err
+	}
+
+	reactions, err := getReactions(ctx, pr.db, entity.ReactionTargetPost, visibleIds, userId)
+	if err != nil {
+		return err
+	}
+
+	mentions, err := getMentions(ctx, pr.db, entity.MentionTargetPost, visibleIds)
+	if err != nil {
+		return err
+	}
+
+	polls, err := getPolls(ctx, pr.db, visibleIds, userId)
+	if err != nil {
+		return err
+	}
+
+	for i := range data {
+		originalId := data[i].Post.ReshareOf
+		if originalId == "" {
+			continue
+		}
+
+		original, ok := originals[originalId]
+		if !ok {
+			data[i].ReshareOf = &dtopost.Reshare{ID: originalId, Unavailable: true}
+			continue
+		}
+
+		post := *original.Post
+		post.Attachments = withAttachments(attachments[originalId])
+		post.Reactions = reactions[originalId]
+		post.Poll = polls[originalId]
+		setMentions(&post, mentions[originalId])
+		original.Post = &post
+		data[i].ReshareOf = &original
+	}
+
+	return nil
+}
+
+// setMentions attaches the mentioned users to a post and links their
+// mentions in its content.
+func setMentions(post *entity.Post, mentions []entity.Mention) {
+	post.Mentions = withMentions(mentions)
+	post.PostInHtml = mention.RenderHtml(post.PostInHtml, mentionNames(mentions))
+}
+
+func mentionIds(mentions []entity.Mention) []string {
+	ids := make([]string, 0, len(mentions))
+	for _, mentioned := range mentions {
+		ids = append(ids, mentioned.UserId)
+	}
+
+	return ids
+}
+
+func nullString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// Update replaces the content and tags of a post, keeping the replaced
+// version as a revision.
+func (pr *PostRepository) Update(ctx context.Context, data entity.Post, revisionId string) error {
+	return db.WithinTransaction(ctx, pr.db, func(ctx context.Context) error {
+		// lock the post so concurrent edits each record the version they replaced
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, `SELECT id FROM posts WHERE id = $1 FOR UPDATE`, data.ID); err != nil {
+			return err
+		}
+
+		revisionSql := `INSERT INTO post_revisions (id, post_id, content, tags, created_at)
+		SELECT $1, posts.id, posts.content, posts.tags, COALESCE(posts.edited_at, posts.created_at)
+		FROM posts WHERE posts.id = $2`
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, revisionSql, revisionId, data.ID); err != nil {
+			return err
+		}
+
+		// the replaced tags stop counting towards trending, the new ones start
+		if err := countTags(ctx, pr.db, data.ID, -1); err != nil {
+			return err
+		}
+
+		sql := `UPDATE posts SET content = $1, tags = $2, edited_at = now() WHERE id = $3`
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, data.PostInHtml, data.Tags, data.ID); err != nil {
+			return err
+		}
+
+		if err := countTags(ctx, pr.db, data.ID, 1); err != nil {
+			return err
+		}
+
+		if err := deleteMentions(ctx, pr.db, entity.MentionTargetPost, data.ID); err != nil {
+			return err
+		}
+
+		return insertMentions(ctx, pr.db, data.ID, entity.MentionTargetPost, data.ID, mentionIds(data.Mentions))
+	})
+}
+
+// GetRevisions lists the previous versions of a post, newest first.
+func (pr *PostRepository) GetRevisions(ctx context.Context, postId string) ([]entity.PostRevision, error) {
+	sql := `SELECT id, post_id, content, tags, created_at FROM post_revisions
+	WHERE post_id = $1
+	ORDER BY created_at desc`
+	rows, err := db.Conn(ctx, pr.db).Query(ctx, sql, postId)
+	if err != nil {
+		return []entity.PostRevision{}, err
+	}
+	defer rows.Close()
+
+	data := make([]entity.PostRevision, 0)
+	for rows.Next() {
+		var revision entity.PostRevision
+		var createdAt time.Time
+		if err := rows.Scan(&revision.ID, &revision.PostId, &revision.PostInHtml, &revision.Tags, &createdAt); err != nil {
+			return []entity.PostRevision{}, err
+		}
+
+		revision.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+		data = append(data, revision)
+	}
+
+	return data, rows.Err()
+}
+
+func setEditedAt(post *entity.Post, editedAt *time.Time) {
+	if editedAt == nil {
+		return
+	}
+
+	post.Edited = true
+	post.EditedAt = editedAt.Format("2006-01-02 15:04:05.999")
+}
+
+// Delete soft deletes a post. A deleted reshare no longer counts towards the
+// reshare count of its original, and a deleted post no longer counts towards
+// trending tags.
+func (pr *PostRepository) Delete(ctx context.Context, postId string) error {
+	return db.WithinTransaction(ctx, pr.db, func(ctx context.Context) error {
+		// lock the post so concurrent deletes adjust the aggregates once
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, `SELECT id FROM posts WHERE id = $1 FOR UPDATE`, postId); err != nil {
+			return err
+		}
+
+		if err := countTags(ctx, pr.db, postId, -1); err != nil {
+			return err
+		}
+
+		if err := unfanOut(ctx, pr.db, postId); err != nil {
+			return err
+		}
+
+		sql := `WITH deleted AS (
+			UPDATE posts SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL
+			RETURNING reshare_of
+		)
+		UPDATE posts SET reshare_count = reshare_count - 1
+		FROM deleted WHERE posts.id = deleted.reshare_of`
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, postId); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// FindDeletedById returns a soft deleted post and whether it was deleted
+// recently enough to still be restored.
+func (pr *PostRepository) FindDeletedById(ctx context.Context, postId string, window time.Duration) (entity.Post, bool, error) {
+	var createdAt time.Time
+	var restorable bool
+	post := entity.Post{}
+	sql := `SELECT id, user_id, content, tags, created_at, deleted_at > now() - make_interval(secs => $2)
+	FROM posts WHERE posts.id = $1 AND posts.deleted_at IS NOT NULL`
+	err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, postId, window.Seconds()).Scan(&post.ID, &post.UserId, &post.PostInHtml, &post.Tags, &createdAt, &restorable)
+	if err != nil {
+		return post, false, err
+	}
+
+	post.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+	return post, restorable, nil
+}
+
+// Restore undoes a soft delete. Restoring a post that is not deleted is a
+// no-op.
+func (pr *PostRepository) Restore(ctx context.Context, postId string) error {
+	return db.WithinTransaction(ctx, pr.db, func(ctx context.Context) error {
+		var reshareOf *string
+		sql := `UPDATE posts SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING reshare_of`
+		if err := db.Conn(ctx, pr.db).QueryRow(ctx, sql, postId).Scan(&reshareOf); err != nil {
+			if err == pgx.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+
+		if reshareOf != nil {
+			sql := `UPDATE posts SET reshare_count = reshare_count + 1 WHERE id = $1`
+			if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, *reshareOf); err != nil {
+				return err
+			}
+		}
+
+		if err := fanOut(ctx, pr.db, postId); err != nil {
+			return err
+		}
+
+		return countTags(ctx, pr.db, postId, 1)
+	})
+}
+
+// Purge permanently removes up to limit posts soft deleted longer than
+// window ago together with everything attached to them. It returns how many
+// posts were removed and the urls of images nothing else uses anymore, no
+// other post, draft, scheduled post or avatar, which the caller should remove
+// from storage.
+func (pr *PostRepository) Purge(ctx context.Context, window time.Duration, limit int) (int64, []string, error) {
+	var purged int64
+	var media []string
+	err := db.WithinTransaction(ctx, pr.db, func(ctx context.Context) error {
+		rows, err := db.Conn(ctx, pr.db).Query(ctx, `SELECT id FROM posts
+		WHERE deleted_at IS NOT NULL AND deleted_at < now() - make_interval(secs => $1)
+		ORDER BY deleted_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, window.Seconds(), limit)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, limit)
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, sql := range []string{
+			`DELETE FROM reactions WHERE target_type = 'comment' AND target_id IN (SELECT id FROM comments WHERE post_id = ANY($1))`,
+			`DELETE FROM reactions WHERE target_type = 'post' AND target_id = ANY($1)`,
+			`DELETE FROM mentions WHERE post_id = ANY($1)`,
+			`DELETE FROM comments WHERE post_id = ANY($1)`,
+			`DELETE FROM post_revisions WHERE post_id = ANY($1)`,
+			`DELETE FROM poll_votes WHERE post_id = ANY($1)`,
+			`DELETE FROM poll_options WHERE post_id = ANY($1)`,
+			`DELETE FROM polls WHERE post_id = ANY($1)`,
+		} {
+			if _, err := db.Conn(ctx, pr.db).Exec(ctx, sql, ids); err != nil {
+				return err
+			}
+		}
+
+		mediaSql := `WITH detached AS (
+			DELETE FROM post_attachments WHERE post_id = ANY($1)
+			RETURNING image_id
+		)
+		DELETE FROM images
+		WHERE images.id IN (SELECT image_id FROM detached)
+		AND NOT EXISTS (
+			SELECT 1 FROM post_attachments
+			WHERE post_attachments.image_id = images.id AND NOT post_attachments.post_id = ANY($1)
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM scheduled_post_attachments WHERE scheduled_post_attachments.image_id = images.id
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM drafts WHERE drafts.attachments @> jsonb_build_array(jsonb_build_object('imageUrl', images.url))
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM users WHERE users.image_url = images.url
+		)
+		RETURNING images.url`
+		mediaRows, err := db.Conn(ctx, pr.db).Query(ctx, mediaSql, ids)
+		if err != nil {
+			return err
+		}
+		for mediaRows.Next() {
+			var url string
+			if err := mediaRows.Scan(&url); err != nil {
+				mediaRows.Close()
+				return err
+			}
+			media = append(media, url)
+		}
+		mediaRows.Close()
+		if err := mediaRows.Err(); err != nil {
+			return err
+		}
+
+		if _, err := db.Conn(ctx, pr.db).Exec(ctx, `DELETE FROM posts WHERE id = ANY($1)`, ids); err != nil {
+			return err
+		}
+
+		purged = int64(len(ids))
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
 
-	return data, count, nil
+	return purged, media, nil
 }
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type ReactionRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewReactionRepo(db *pgxpool.Pool, log *zap.Logger) *ReactionRepository {
+	return &ReactionRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// Toggle applies a reaction from a user. Reacting again with the same type
+// removes the reaction, reacting with another type replaces it. It returns
+// the user's reaction afterwards, "" when it was removed.
+func (rr *ReactionRepository) Toggle(ctx context.Context, data entity.Reaction) (string, error) {
+	result := data.Type
+	err := db.WithinTransaction(ctx, rr.db, func(ctx context.Context) error {
+		// the upsert writes nothing when the same reaction is there already,
+		// but still locks it, so concurrent toggles apply one after the other
+		// even when no reaction existed yet
+		sql := `INSERT INTO reactions (target_type, target_id, user_id, type, created_at) VALUES ($1,$2,$3,$4,now())
+		ON CONFLICT (target_type, target_id, user_id) DO UPDATE SET type = EXCLUDED.type, created_at = EXCLUDED.created_at
+		WHERE reactions.type <> EXCLUDED.type
+		RETURNING type`
+		var applied string
+		err := db.Conn(ctx, rr.db).QueryRow(ctx, sql, data.TargetType, data.TargetId, data.UserId, data.Type).Scan(&applied)
+		if err != pgx.ErrNoRows {
+			return err
+		}
+
+		result = ""
+		sql = `DELETE FROM reactions WHERE target_type = $1 AND target_id = $2 AND user_id = $3`
+		_, err = db.Conn(ctx, rr.db).Exec(ctx, sql, data.TargetType, data.TargetId, data.UserId)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// getReactions summarizes the reactions on targetIds, keyed by target id.
+// Every requested target gets an entry; userId may be empty for anonymous
+// callers.
+func getReactions(ctx context.Context, pool *pgxpool.Pool, targetType string, targetIds []string, userId string) (map[string]entity.Reactions, error) {
+	sql := `SELECT target_id, type, count(user_id), bool_or(user_id::text = $3)
+	FROM reactions
+	WHERE target_type = $1 AND target_id = ANY($2)
+	GROUP BY target_id, type`
+	rows, err := db.Conn(ctx, pool).Query(ctx, sql, targetType, targetIds, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	data := make(map[string]entity.Reactions, len(targetIds))
+	for _, targetId := range targetIds {
+		data[targetId] = entity.NewReactions()
+	}
+
+	for rows.Next() {
+		var (
+			targetId     string
+			reactionType string
+			count        int64
+			mine         bool
+		)
+		if err := rows.Scan(&targetId, &reactionType, &count, &mine); err != nil {
+			return nil, err
+		}
+
+		reactions := data[targetId]
+		reactions.Counts[reactionType] = count
+		if mine {
+			reactions.Mine = reactionType
+		}
+		data[targetId] = reactions
+	}
+
+	return data, rows.Err()
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type ScheduledPostRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewScheduledPostRepo(db *pgxpool.Pool, log *zap.Logger) *ScheduledPostRepository {
+	return &ScheduledPostRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (sr *ScheduledPostRepository) Insert(ctx context.Context, data entity.ScheduledPost, publishAt time.Time) error {
+	return db.WithinTransaction(ctx, sr.db, func(ctx context.Context) error {
+		sql := `INSERT INTO scheduled_posts (id, user_id, content, tags, visibility, publish_at, created_at) VALUES ($1,$2,$3,$4,$5,$6,now())`
+		if _, err := db.Conn(ctx, sr.db).Exec(ctx, sql, data.ID, data.UserId, data.PostInHtml, data.Tags, data.Visibility, publishAt); err != nil {
+			return err
+		}
+
+		return sr.insertAttachments(ctx, data)
+	})
+}
+
+func (sr *ScheduledPostRepository) insertAttachments(ctx context.Context, data entity.ScheduledPost) error {
+	for _, attachment := range data.Attachments {
+		sql := `INSERT INTO scheduled_post_attachments (scheduled_post_id, image_id, alt_text, position) VALUES ($1,$2,$3,$4)`
+		if _, err := db.Conn(ctx, sr.db).Exec(ctx, sql, data.ID, attachment.ImageId, attachment.AltText, attachment.Position); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetByUser returns the posts userId has scheduled, the next to be published
+// first.
+func (sr *ScheduledPostRepository) GetByUser(ctx context.Context, userId string) ([]entity.ScheduledPost, error) {
+	sql := `SELECT id, user_id, content, tags, visibility, publish_at, attempts, last_error, created_at
+	FROM scheduled_posts WHERE user_id = $1
+	ORDER BY publish_at, id`
+	rows, err := db.Conn(ctx, sr.db).Query(ctx, sql, userId)
+	if err != nil {
+		return []entity.ScheduledPost{}, err
+	}
+
+	data := make([]entity.ScheduledPost, 0)
+	for rows.Next() {
+		post, err := scanScheduledPost(rows)
+		if err != nil {
+			rows.Close()
+			return []entity.ScheduledPost{}, err
+		}
+		data = append(data, post)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return []entity.ScheduledPost{}, err
+	}
+
+	if err := sr.setAttachments(ctx, data); err != nil {
+		return []entity.ScheduledPost{}, err
+	}
+
+	return data, nil
+}
+
+func (sr *ScheduledPostRepository) FindById(ctx context.Context, id string) (entity.ScheduledPost, error) {
+	sql := `SELECT id, user_id, content, tags, visibility, publish_at, attempts, last_error, created_at
+	FROM scheduled_posts WHERE id = $1`
+	post, err := scanScheduledPost(db.Conn(ctx, sr.db).QueryRow(ctx, sql, id))
+	if err != nil {
+		return entity.ScheduledPost{}, err
+	}
+
+	data := []entity.ScheduledPost{post}
+	if err := sr.setAttachments(ctx, data); err != nil {
+		return entity.ScheduledPost{}, err
+	}
+
+	return data[0], nil
+}
+
+// Update replaces the content and publish time of a scheduled post and clears
+// its failed attempts. It returns pgx.ErrNoRows when the post was published or
+// cancelled in the meantime.
+func (sr *ScheduledPostRepository) Update(ctx context.Context, data entity.ScheduledPost, publishAt time.Time) error {
+	return db.WithinTransaction(ctx, sr.db, func(ctx context.Context) error {
+		sql := `UPDATE scheduled_posts
+		SET content = $2, tags = $3, visibility = $4, publish_at = $5, attempts = 0, last_error = NULL, retry_at = NULL
+		WHERE id = $1`
+		tag, err := db.Conn(ctx, sr.db).Exec(ctx, sql, data.ID, data.PostInHtml, data.Tags, data.Visibility, publishAt)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		if _, err := db.Conn(ctx, sr.db).Exec(ctx, `DELETE FROM scheduled_post_attachments WHERE scheduled_post_id = $1`, data.ID); err != nil {
+			return err
+		}
+
+		return sr.insertAttachments(ctx, data)
+	})
+}
+
+// Delete removes a scheduled post. It returns pgx.ErrNoRows when the post was
+// published or cancelled in the meantime.
+func (sr *ScheduledPostRepository) Delete(ctx context.Context, id string) error {
+	tag, err := db.Conn(ctx, sr.db).Exec(ctx, `DELETE FROM scheduled_posts WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// ClaimDue locks the scheduled post that has been due the longest, skipping
+// posts other instances have locked, posts waiting to be retried and posts
+// that failed maxAttempts times. It returns pgx.ErrNoRows when none is due.
+// It must run in a transaction; the lock holds until it ends, so the caller
+// publishes and deletes the post in that same transaction.
+func (sr *ScheduledPostRepository) ClaimDue(ctx context.Context, maxAttempts int) (entity.ScheduledPost, error) {
+	sql := `SELECT id, user_id, content, tags, visibility, publish_at, attempts, last_error, created_at
+	FROM scheduled_posts
+	WHERE publish_at <= now() AND attempts < $1 AND (retry_at IS NULL OR retry_at <= now())
+	ORDER BY publish_at
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED`
+	post, err := scanScheduledPost(db.Conn(ctx, sr.db).QueryRow(ctx, sql, maxAttempts))
+	if err != nil {
+		return entity.ScheduledPost{}, err
+	}
+
+	data := []entity.ScheduledPost{post}
+	if err := sr.setAttachments(ctx, data); err != nil {
+		return entity.ScheduledPost{}, err
+	}
+
+	return data[0], nil
+}
+
+// Fail records a failed attempt to publish a scheduled post, which is not
+// claimed again before retryAt.
+func (sr *ScheduledPostRepository) Fail(ctx context.Context, id, lastError string, retryAt time.Time) error {
+	sql := `UPDATE scheduled_posts SET attempts = attempts + 1, last_error = $2, retry_at = $3 WHERE id = $1`
+	_, err := db.Conn(ctx, sr.db).Exec(ctx, sql, id, lastError, retryAt)
+	return err
+}
+
+func (sr *ScheduledPostRepository) setAttachments(ctx context.Context, data []entity.ScheduledPost) error {
+	ids := make([]string, 0, len(data))
+	for _, post := range data {
+		ids = append(ids, post.ID)
+	}
+
+	sql := `SELECT scheduled_post_attachments.scheduled_post_id, images.id, images.url, scheduled_post_attachments.alt_text, scheduled_post_attachments.position
+	FROM scheduled_post_attachments
+	JOIN images ON images.id = scheduled_post_attachments.image_id
+	WHERE scheduled_post_attachments.scheduled_post_id = ANY($1)
+	ORDER BY scheduled_post_attachments.position`
+	rows, err := db.Conn(ctx, sr.db).Query(ctx, sql, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	attachments := make(map[string][]entity.Attachment)
+	for rows.Next() {
+		var postId string
+		var attachment entity.Attachment
+		if err := rows.Scan(&postId, &attachment.ImageId, &attachment.ImageUrl, &attachment.AltText, &attachment.Position); err != nil {
+			return err
+		}
+		attachments[postId] = append(attachments[postId], attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range data {
+		data[i].Attachments = withAttachments(attachments[data[i].ID])
+	}
+
+	return nil
+}
+
+func scanScheduledPost(row pgx.Row) (entity.ScheduledPost, error) {
+	var post entity.ScheduledPost
+	var publishAt, createdAt time.Time
+	var lastError *string
+	if err := row.Scan(&post.ID, &post.UserId, &post.PostInHtml, &post.Tags, &post.Visibility, &publishAt, &post.Attempts, &lastError, &createdAt); err != nil {
+		return entity.ScheduledPost{}, err
+	}
+	if lastError != nil {
+		post.LastError = *lastError
+	}
+
+	post.PublishAt = publishAt.UTC().Format(time.RFC3339)
+	post.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+	return post, nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/friend"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type SuggestionRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewSuggestionRepo(db *pgxpool.Pool, log *zap.Logger) *SuggestionRepository {
+	return &SuggestionRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetUserIds pages through every user id greater than after, so the
+// suggestion job can walk the users table in batches.
+func (sr *SuggestionRepository) GetUserIds(ctx context.Context, after string, limit int) ([]string, error) {
+	sql := `SELECT id FROM users WHERE id > $1 ORDER BY id LIMIT $2`
+	if after == "" {
+		after = "00000000-0000-0000-0000-000000000000"
+	}
+
+	rows, err := db.Conn(ctx, sr.db).Query(ctx, sql, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, limit)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Refresh recomputes the stored suggestions of a single user. Candidates are
+// friends of friends and authors sharing post tags with the user, ranked by
+// mutual friends first and shared tags second.
+func (sr *SuggestionRepository) Refresh(ctx context.Context, userId string, limit int) error {
+	return db.WithinTransaction(ctx, sr.db, func(ctx context.Context) error {
+		if _, err := db.Conn(ctx, sr.db).Exec(ctx, `DELETE FROM friend_suggestions WHERE user_id = $1`, userId); err != nil {
+			return err
+		}
+
+		sql := `WITH my_friends AS (
+			SELECT friend_id FROM friends WHERE user_id = $1
+		),
+		mutual AS (
+			SELECT friends.friend_id AS candidate_id, count(friends.user_id) AS mutual_count
+			FROM friends
+			WHERE friends.user_id IN (SELECT friend_id FROM my_friends)
+			GROUP BY friends.friend_id
+		),
+		my_tags AS (
+			SELECT DISTINCT unnest(tags) AS tag FROM posts WHERE user_id = $1 AND deleted_at IS NULL
+		),
+		-- only the candidates' public posts count, the shared tag count is
+		-- shown to the caller; the overlap filter lets the tags index narrow
+		-- the posts before they are unnested
+		shared AS (
+			SELECT posts.user_id AS candidate_id, count(DISTINCT post_tags.tag) AS shared_tag_count
+			FROM posts
+			CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+			WHERE posts.tags && (SELECT array_agg(tag) FROM my_tags)
+			AND post_tags.tag IN (SELECT tag FROM my_tags)
+			AND posts.visibility = 'public'
+			AND posts.deleted_at IS NULL
+			GROUP BY posts.user_id
+		),
+		candidates AS (
+			SELECT
+				COALESCE(mutual.candidate_id, shared.candidate_id) AS candidate_id,
+				COALESCE(mutual.mutual_count, 0) AS mutual_count,
+				COALESCE(shared.shared_tag_count, 0) AS shared_tag_count
+			FROM mutual
+			FULL OUTER JOIN shared ON shared.candidate_id = mutual.candidate_id
+		)
+		INSERT INTO friend_suggestions (user_id, suggested_id, mutual_count, shared_tag_count, created_at)
+		SELECT $1, candidate_id, mutual_count, shared_tag_count, $2
+		FROM candidates
+		WHERE candidate_id <> $1
+		AND candidate_id NOT IN (SELECT friend_id FROM my_friends)
+		ORDER BY mutual_count desc, shared_tag_count desc
+		LIMIT $3`
+		if _, err := db.Conn(ctx, sr.db).Exec(ctx, sql, userId, time.Now(), limit); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (sr *SuggestionRepository) GetSuggestions(ctx context.Context, userId string, filter dto.SuggestionFilter) ([]dto.Suggestion, int64, error) {
+	// friendships formed after the last refresh are filtered out on read
+	where := `WHERE friend_suggestions.user_id = $1
+	AND NOT EXISTS (
+		SELECT 1 FROM friends
+		WHERE friends.user_id = $1 AND friends.friend_id = friend_suggestions.suggested_id
+	)`
+
+	var total int64
+	if err := db.Conn(ctx, sr.db).QueryRow(ctx, `SELECT count(suggested_id) FROM friend_suggestions `+where, userId).Scan(&total); err != nil {
+		return []dto.Suggestion{}, 0, err
+	}
+
+	sql := `SELECT
+		users.id,
+		users.name,
+		users.image_url,
+		users.friend_count,
+		users.created_at,
+		friend_suggestions.mutual_count,
+		friend_suggestions.shared_tag_count
+	FROM friend_suggestions
+	JOIN users ON users.id = friend_suggestions.suggested_id
+	` + where + `
+	ORDER BY friend_suggestions.mutual_count desc, friend_suggestions.shared_tag_count desc, users.id
+	LIMIT $2 OFFSET $3`
+	rows, err := db.Conn(ctx, sr.db).Query(ctx, sql, userId, filter.Limit, filter.Offset)
+	if err != nil {
+		return []dto.Suggestion{}, 0, err
+	}
+	defer rows.Close()
+
+	data := make([]dto.Suggestion, 0)
+	for rows.Next() {
+		var suggestion dto.Suggestion
+		var createdAt time.Time
+		err := rows.Scan(
+			&suggestion.ID,
+			&suggestion.Name,
+			&suggestion.ImageUrl,
+			&suggestion.FriendCount,
+			&createdAt,
+			&suggestion.MutualFriendCount,
+			&suggestion.SharedTagCount)
+		if err != nil {
+			return []dto.Suggestion{}, 0, err
+		}
+
+		suggestion.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
+		data = append(data, suggestion)
+	}
+
+	return data, total, rows.Err()
+}
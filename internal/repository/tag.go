@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+	"go.uber.org/zap"
+)
+
+type TagRepository struct {
+	db  *pgxpool.Pool
+	log *zap.Logger
+}
+
+func NewTagRepo(db *pgxpool.Pool, log *zap.Logger) *TagRepository {
+	return &TagRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetTrending ranks tags by how much faster they are used in the last window
+// than in the window before it. Public posts come from the hourly aggregates;
+// signed-in callers additionally count the non-public posts they can see,
+// from the per-author aggregates of the authors their friendships reach and
+// with the same rule as visibleTo.
+func (tr *TagRepository) GetTrending(ctx context.Context, window time.Duration, limit int64, userId string) ([]entity.TrendingTag, error) {
+	args := []any{window.Seconds(), limit}
+	private := ""
+	if userId != "" {
+		args = append(args, userId)
+		// scope 0 is the caller, 1 their friends and 2 friends of friends
+		private = `UNION ALL
+		SELECT counts.tag, counts.bucket, counts.post_count
+		FROM (
+			SELECT author_id, min(scope) AS scope
+			FROM (
+				SELECT $3::uuid, 0
+				UNION ALL
+				SELECT friends.user_id, 1 FROM friends WHERE friends.friend_id = $3
+				UNION ALL
+				SELECT author_friends.user_id, 2
+				FROM friends second
+				JOIN friends author_friends ON author_friends.friend_id = second.user_id
+				WHERE second.friend_id = $3
+			) reachable (author_id, scope)
+			GROUP BY author_id
+		) authors
+		JOIN tag_author_hourly_counts counts ON counts.author_id = authors.author_id
+		WHERE counts.bucket >= date_trunc('hour', now() - make_interval(secs => $1 * 2))
+		AND (authors.scope = 0
+			or (authors.scope = 1 AND counts.visibility IN ('friends', 'friends-of-friends'))
+			or (authors.scope = 2 AND counts.visibility = 'friends-of-friends'))`
+	}
+
+	sql := `WITH counts AS (
+		SELECT tag, bucket, post_count
+		FROM tag_hourly_counts
+		WHERE bucket >= date_trunc('hour', now() - make_interval(secs => $1 * 2))
+		` + private + `
+	),
+	windows AS (
+		SELECT
+			tag,
+			COALESCE(sum(post_count) FILTER (WHERE bucket >= date_trunc('hour', now() - make_interval(secs => $1))), 0) AS recent,
+			COALESCE(sum(post_count) FILTER (WHERE bucket < date_trunc('hour', now() - make_interval(secs => $1))), 0) AS previous
+		FROM counts
+		GROUP BY tag
+	)
+	SELECT tag, recent, previous, (recent - previous) / sqrt(previous + 1) AS score
+	FROM windows
+	WHERE recent > 0
+	ORDER BY score desc, recent desc, tag
+	LIMIT $2`
+	rows, err := db.Conn(ctx, tr.db).Query(ctx, sql, args...)
+	if err != nil {
+		return []entity.TrendingTag{}, err
+	}
+	defer rows.Close()
+
+	data := make([]entity.TrendingTag, 0)
+	for rows.Next() {
+		var tag entity.TrendingTag
+		if err := rows.Scan(&tag.Tag, &tag.PostCount, &tag.PreviousCount, &tag.Score); err != nil {
+			return []entity.TrendingTag{}, err
+		}
+		data = append(data, tag)
+	}
+
+	return data, rows.Err()
+}
+
+// Autocomplete suggests known tags starting with prefix, most used in public
+// posts first. Tags userId used within recent, in posts of any visibility,
+// come before all others.
+func (tr *TagRepository) Autocomplete(ctx context.Context, prefix string, limit int64, userId string, recent time.Duration) ([]entity.TagUsage, error) {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	args := []any{escaper.Replace(prefix) + "%", limit}
+
+	mine := `SELECT NULL::varchar AS tag, 0::bigint AS uses WHERE false`
+	if userId != "" {
+		args = append(args, userId, recent.Seconds())
+		mine = `SELECT post_tags.tag, count(posts.id) AS uses
+		FROM posts
+		CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+		WHERE posts.user_id = $3
+		AND posts.deleted_at IS NULL
+		AND posts.created_at > now() - make_interval(secs => $4)
+		AND post_tags.tag LIKE $1
+		GROUP BY post_tags.tag`
+	}
+
+	sql := `WITH mine AS (
+		` + mine + `
+	),
+	popular AS (
+		SELECT name FROM tags
+		WHERE name LIKE $1 AND usage_count > 0
+		ORDER BY usage_count desc
+		LIMIT $2
+	)
+	SELECT candidates.tag, COALESCE(tags.usage_count, 0) AS usage_count, mine.uses IS NOT NULL
+	FROM (SELECT name AS tag FROM popular UNION SELECT tag FROM mine) candidates
+	LEFT JOIN tags ON tags.name = candidates.tag
+	LEFT JOIN mine ON mine.tag = candidates.tag
+	ORDER BY mine.uses desc nulls last, usage_count desc, candidates.tag
+	LIMIT $2`
+	rows, err := db.Conn(ctx, tr.db).Query(ctx, sql, args...)
+	if err != nil {
+		return []entity.TagUsage{}, err
+	}
+	defer rows.Close()
+
+	data := make([]entity.TagUsage, 0)
+	for rows.Next() {
+		var usage entity.TagUsage
+		if err := rows.Scan(&usage.Tag, &usage.UsageCount, &usage.RecentlyUsed); err != nil {
+			return []entity.TagUsage{}, err
+		}
+		data = append(data, usage)
+	}
+
+	return data, rows.Err()
+}
+
+// PruneCounts drops hourly aggregates older than retention and returns how
+// many were removed.
+func (tr *TagRepository) PruneCounts(ctx context.Context, retention time.Duration) (int64, error) {
+	var pruned int64
+	for _, sql := range []string{
+		`DELETE FROM tag_hourly_counts WHERE bucket < now() - make_interval(secs => $1)`,
+		`DELETE FROM tag_author_hourly_counts WHERE bucket < now() - make_interval(secs => $1)`,
+	} {
+		result, err := db.Conn(ctx, tr.db).Exec(ctx, sql, retention.Seconds())
+		if err != nil {
+			return 0, err
+		}
+		pruned += result.RowsAffected()
+	}
+
+	return pruned, nil
+}
+
+// countTags moves the usage counts and the hourly aggregates of the tags of a
+// post by delta. Only posts that are not deleted are counted, public ones in
+// the usage counts and the hourly aggregates and the others in the per-author
+// aggregates, so callers add a post after it becomes countable and remove it
+// before it stops being countable.
+func countTags(ctx context.Context, pool *pgxpool.Pool, postId string, delta int) error {
+	usageSql := `INSERT INTO tags (name, usage_count)
+	SELECT DISTINCT post_tags.tag, $2::integer
+	FROM posts
+	CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+	WHERE posts.id = $1 AND posts.visibility = 'public' AND posts.deleted_at IS NULL
+	ON CONFLICT (name) DO UPDATE SET usage_count = tags.usage_count + EXCLUDED.usage_count`
+	if _, err := db.Conn(ctx, pool).Exec(ctx, usageSql, postId, delta); err != nil {
+		return err
+	}
+
+	sql := `INSERT INTO tag_hourly_counts (tag, bucket, post_count)
+	SELECT DISTINCT post_tags.tag, date_trunc('hour', posts.created_at), $2::integer
+	FROM posts
+	CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+	WHERE posts.id = $1 AND posts.visibility = 'public' AND posts.deleted_at IS NULL
+	ON CONFLICT (tag, bucket) DO UPDATE SET post_count = tag_hourly_counts.post_count + EXCLUDED.post_count`
+	if _, err := db.Conn(ctx, pool).Exec(ctx, sql, postId, delta); err != nil {
+		return err
+	}
+
+	authorSql := `INSERT INTO tag_author_hourly_counts (author_id, visibility, tag, bucket, post_count)
+	SELECT DISTINCT posts.user_id, posts.visibility, post_tags.tag, date_trunc('hour', posts.created_at), $2::integer
+	FROM posts
+	CROSS JOIN LATERAL unnest(posts.tags) AS post_tags(tag)
+	WHERE posts.id = $1 AND posts.visibility <> 'public' AND posts.deleted_at IS NULL
+	ON CONFLICT (author_id, bucket, visibility, tag) DO UPDATE SET post_count = tag_author_hourly_counts.post_count + EXCLUDED.post_count`
+	if _, err := db.Conn(ctx, pool).Exec(ctx, authorSql, postId, delta); err != nil {
+		return err
+	}
+
+	return nil
+}
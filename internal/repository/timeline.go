@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+)
+
+// fanOut adds a post to its author's home timeline and, when the post is
+// fanned out, to the home timelines of the author's friends. Posts that are
+// not fanned out reach the friends' feeds when the feed is read.
+func fanOut(ctx context.Context, pool *pgxpool.Pool, postId string) error {
+	sql := `INSERT INTO home_timeline (user_id, post_id, author_id, created_at)
+	SELECT posts.user_id, posts.id, posts.user_id, posts.created_at FROM posts WHERE posts.id = $1
+	UNION ALL
+	SELECT friends.friend_id, posts.id, posts.user_id, posts.created_at
+	FROM posts
+	JOIN friends ON friends.user_id = posts.user_id
+	WHERE posts.id = $1 AND posts.fanned_out
+	ON CONFLICT (user_id, post_id) DO NOTHING`
+	_, err := db.Conn(ctx, pool).Exec(ctx, sql, postId)
+	return err
+}
+
+// unfanOut removes a post from every home timeline.
+func unfanOut(ctx context.Context, pool *pgxpool.Pool, postId string) error {
+	_, err := db.Conn(ctx, pool).Exec(ctx, `DELETE FROM home_timeline WHERE post_id = $1`, postId)
+	return err
+}
+
+// linkTimelines copies the fanned out posts of two new friends into each
+// other's home timeline.
+func linkTimelines(ctx context.Context, pool *pgxpool.Pool, userId, friendId string) error {
+	sql := `INSERT INTO home_timeline (user_id, post_id, author_id, created_at)
+	SELECT CASE WHEN posts.user_id = $1 THEN $2::uuid ELSE $1::uuid END, posts.id, posts.user_id, posts.created_at
+	FROM posts
+	WHERE posts.user_id IN ($1, $2) AND posts.fanned_out AND posts.deleted_at IS NULL
+	ON CONFLICT (user_id, post_id) DO NOTHING`
+	_, err := db.Conn(ctx, pool).Exec(ctx, sql, userId, friendId)
+	return err
+}
+
+// unlinkTimelines removes the posts of two former friends from each other's
+// home timeline.
+func unlinkTimelines(ctx context.Context, pool *pgxpool.Pool, userId, friendId string) error {
+	sql := `DELETE FROM home_timeline
+	WHERE (user_id = $1 AND author_id = $2) or (user_id = $2 AND author_id = $1)`
+	_, err := db.Conn(ctx, pool).Exec(ctx, sql, userId, friendId)
+	return err
+}
+
+// timelineSource is one of the places a signed-in viewer's feed is read
+// from: a FROM clause joining posts and their authors, filtered to the
+// viewer, and the keyset it is read in.
+type timelineSource struct {
+	from string
+	feed keyset
+}
+
+// timelineSources lists the sources of the viewer's feed: the materialized
+// home timeline, the posts of friends too popular to fan out and, for the
+// following feed, the posts of followed users. viewer is the placeholder
+// holding the viewer's id.
+func timelineSources(viewer, feed string) []timelineSource {
+	sources := []timelineSource{
+		{
+			from: fmt.Sprintf(`FROM home_timeline
+			JOIN posts ON posts.id = home_timeline.post_id
+			JOIN users ON posts.user_id = users.id
+			WHERE home_timeline.user_id = %s`, viewer),
+			feed: keyset{column: "home_timeline.created_at", idColumn: "home_timeline.post_id", desc: true},
+		},
+		{
+			from: fmt.Sprintf(`FROM friends
+			JOIN posts ON posts.user_id = friends.user_id AND NOT posts.fanned_out
+			JOIN users ON posts.user_id = users.id
+			WHERE friends.friend_id = %s`, viewer),
+			feed: keyset{column: "posts.created_at", idColumn: "posts.id", desc: true},
+		},
+	}
+	if feed == "following" {
+		sources = append(sources, timelineSource{
+			from: fmt.Sprintf(`FROM follows
+			JOIN posts ON posts.user_id = follows.following_id
+			JOIN users ON posts.user_id = users.id
+			WHERE follows.follower_id = %s`, viewer),
+			feed: keyset{column: "posts.created_at", idColumn: "posts.id", desc: true},
+		})
+	}
+
+	return sources
+}
+
+// timelineQuery selects the ids of at most limit posts matching cond from
+// each source, read from the position in page when it has one. cond refers to
+// args; the returned args add the position. The union holds every post of the
+// first limit in the merged feed.
+func timelineQuery(sources []timelineSource, cond string, args []any, page cursor.Page, after time.Time, limit int64) (string, []any) {
+	args = append([]any{}, args...)
+	var key, id string
+	if page.Id != "" {
+		args = append(args, after, page.Id)
+		key, id = fmt.Sprintf("$%d", len(args)-1), fmt.Sprintf("$%d", len(args))
+	}
+
+	parts := make([]string, 0, len(sources))
+	for _, source := range sources {
+		where := cond
+		if key != "" {
+			where += " AND " + source.feed.after(page, key, id)
+		}
+		parts = append(parts, fmt.Sprintf(`(SELECT posts.id %s AND %s ORDER BY %s LIMIT %d)`,
+			source.from, where, source.feed.order(page), limit))
+	}
+
+	return strings.Join(parts, "\nUNION\n"), args
+}
+
+// timelineIds runs a query built by timelineQuery.
+func timelineIds(ctx context.Context, pool *pgxpool.Pool, sql string, args []any) ([]string, error) {
+	rows, err := db.Conn(ctx, pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
+)
+
+// countUpTo counts the rows a query returns, reading at most limit+1 of them
+// so large result sets stay cheap. When there are more than limit rows the
+// count stops at limit and is flagged as an estimate.
+func countUpTo(ctx context.Context, pool *pgxpool.Pool, query string, args []any, limit int64) (int64, bool, error) {
+	var count int64
+	sql := fmt.Sprintf(`SELECT count(*) FROM (%s LIMIT %d) matches`, query, limit+1)
+	if err := db.Conn(ctx, pool).QueryRow(ctx, sql, args...).Scan(&count); err != nil {
+		return 0, false, err
+	}
+
+	if count > limit {
+		return limit, true, nil
+	}
+
+	return count, false, nil
+}
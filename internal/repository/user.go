@@ -3,11 +3,15 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shafaalafghany/segokuning-social-app/internal/common/utils/cursor"
+	metadto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/meta"
 	dto "github.com/shafaalafghany/segokuning-social-app/internal/domain/dto/user"
 	"github.com/shafaalafghany/segokuning-social-app/internal/entity"
+	"github.com/shafaalafghany/segokuning-social-app/pkg/db"
 	"go.uber.org/zap"
 )
 
@@ -31,7 +35,7 @@ func (ur *UserRepository) FindById(ctx context.Context, userId string) (*entity.
 	res := &entity.User{}
 	sql := `SELECT id, name, COALESCE(email, ''), COALESCE(phone, ''), password FROM users WHERE id = $1`
 
-	err := ur.db.QueryRow(ctx, sql, userId).Scan(&res.ID, &res.Name, &res.Email, &res.Phone, &res.Password)
+	err := db.Conn(ctx, ur.db).QueryRow(ctx, sql, userId).Scan(&res.ID, &res.Name, &res.Email, &res.Phone, &res.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +47,7 @@ func (ur *UserRepository) FindByEmail(ctx context.Context, email string) (*entit
 	res := &entity.User{}
 	sql := `SELECT id, name, email, COALESCE(phone, ''), password FROM users WHERE email = $1`
 
-	err := ur.db.QueryRow(ctx, sql, email).Scan(&res.ID, &res.Name, &res.Email, &res.Phone, &res.Password)
+	err := db.Conn(ctx, ur.db).QueryRow(ctx, sql, email).Scan(&res.ID, &res.Name, &res.Email, &res.Phone, &res.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +59,7 @@ func (ur *UserRepository) FindByPhone(ctx context.Context, phone string) (*entit
 	res := &entity.User{}
 	sql := `SELECT id, name, COALESCE(email, ''), phone, password FROM users WHERE phone = $1`
 
-	err := ur.db.QueryRow(ctx, sql, phone).Scan(&res.ID, &res.Name, &res.Email, &res.Phone, &res.Password)
+	err := db.Conn(ctx, ur.db).QueryRow(ctx, sql, phone).Scan(&res.ID, &res.Name, &res.Email, &res.Phone, &res.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -63,27 +67,75 @@ func (ur *UserRepository) FindByPhone(ctx context.Context, phone string) (*entit
 	return res, nil
 }
 
-func (ur *UserRepository) GetUserWithFilter(ctx context.Context, userId string, filter dto.UserFilter) ([]entity.User, int64, error) {
-	sort := "users.created_at"
-	if !(filter.SortBy == "") && (filter.SortBy != "createdAt") {
-		sort = "users.friend_count"
+// GetUserWithFilter returns a page of users other than userId, sorted by
+// filter.SortBy and then by id. Pages are read from filter.Cursor when set,
+// otherwise from filter.Offset.
+func (ur *UserRepository) GetUserWithFilter(ctx context.Context, userId string, filter dto.UserFilter) ([]entity.User, metadto.Meta, error) {
+	users := keyset{column: "users.created_at", idColumn: "users.id", desc: filter.OrderBy != "asc"}
+	if filter.SortBy == "friendCount" {
+		users.column = "users.friend_count"
 	}
-	order := "desc"
-	if !(filter.OrderBy == "") {
-		order = filter.OrderBy
+
+	page := cursor.Page{Direction: cursor.Next}
+	var after any
+	if filter.Cursor != "" {
+		var err error
+		page, err = cursor.DecodePage(filter.Cursor)
+		if err != nil {
+			return []entity.User{}, metadto.Meta{}, err
+		}
+		// the key has to match the sort the cursor was made for
+		if filter.SortBy == "friendCount" {
+			after, err = strconv.ParseInt(page.Key, 10, 64)
+		} else {
+			after, err = time.Parse(time.RFC3339Nano, page.Key)
+		}
+		if err != nil {
+			return []entity.User{}, metadto.Meta{}, cursor.ErrInvalid
+		}
 	}
-	where := fmt.Sprintf(" WHERE users.id <> '%s'", userId)
+
+	args := make([]any, 0)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := " WHERE users.id <> " + arg(userId)
 	join := ""
 	if filter.OnlyFriend {
-		where += fmt.Sprintf(" AND friends.user_id = '%s'", userId)
+		where += " AND friends.user_id = " + arg(userId)
 		join = " JOIN friends ON users.id = friends.friend_id"
 	}
 
+	if filter.ListId != "" {
+		where += " AND friend_list_members.list_id = " + arg(filter.ListId)
+		join += " JOIN friend_list_members ON users.id = friend_list_members.friend_id"
+	}
+
 	if filter.Search != "" {
-		where += " AND users.name LIKE '%" + filter.Search + "%'"
+		where += " AND users.name LIKE " + arg("%"+filter.Search+"%")
 	}
 
-	rows, err := ur.db.Query(ctx,
+	var (
+		total     int64
+		estimated bool
+	)
+	if filter.CountLimit > 0 {
+		var err error
+		total, estimated, err = countUpTo(ctx, ur.db, "SELECT 1 FROM users"+join+where, args, filter.CountLimit)
+		if err != nil {
+			return []entity.User{}, metadto.Meta{}, err
+		}
+	}
+
+	offset := filter.Offset
+	if filter.Cursor != "" {
+		where += " AND " + users.after(page, arg(after), arg(page.Id))
+		offset = 0
+	}
+
+	rows, err := db.Conn(ctx, ur.db).Query(ctx,
 		fmt.Sprintf(`SELECT 
 		users.id, 
 		users.name, 
@@ -91,30 +143,48 @@ func (ur *UserRepository) GetUserWithFilter(ctx context.Context, userId string,
 		users.friend_count, 
 		users.created_at 
 		FROM users %s %s 
-		ORDER BY %s %s 
+		ORDER BY %s 
 		LIMIT %d 
-		OFFSET %d`, join, where, sort, order, filter.Limit, filter.Offset))
+		OFFSET %d`, join, where, users.order(page), filter.Limit+1, offset), args...)
 	if err != nil {
-		return []entity.User{}, 0, err
+		return []entity.User{}, metadto.Meta{}, err
 	}
 
 	data := make([]entity.User, 0)
-	var count int64 = 0
+	positions := make([]cursor.Page, 0)
 	for rows.Next() {
 		var user entity.User
 		var createdAt time.Time
 		err := rows.Scan(&user.ID, &user.Name, &user.ImageUrl, &user.FriendCount, &createdAt)
 		if err != nil {
-			return []entity.User{}, 0, err
+			rows.Close()
+			return []entity.User{}, metadto.Meta{}, err
 		}
 
 		user.CreatedAt = createdAt.Format("2006-01-02 15:04:05.999")
 		data = append(data, user)
-		count += 1
+
+		key := createdAt.Format(time.RFC3339Nano)
+		if filter.SortBy == "friendCount" {
+			key = strconv.FormatInt(user.FriendCount, 10)
+		}
+		positions = append(positions, cursor.Page{Key: key, Id: user.ID})
 	}
 	rows.Close()
+	if err := rows.Err(); err != nil {
+		return []entity.User{}, metadto.Meta{}, err
+	}
+
+	data, next, prev := keysetCursors(data, positions, filter.Limit, page, filter.Cursor != "" || offset > 0)
 
-	return data, count, nil
+	return data, metadto.Meta{
+		Limit:          filter.Limit,
+		Offset:         offset,
+		Total:          total,
+		TotalEstimated: estimated,
+		NextCursor:     next,
+		PrevCursor:     prev,
+	}, nil
 }
 
 func (ur *UserRepository) Insert(ctx context.Context, data entity.User, credType string) error {
@@ -123,12 +193,12 @@ func (ur *UserRepository) Insert(ctx context.Context, data entity.User, credType
 	switch credType {
 	case "phone":
 		sql = `INSERT INTO users (id,phone,name,password,friend_count,created_at) VALUES ($1,$2,$3,$4,$5,now())`
-		if _, err := ur.db.Exec(ctx, sql, data.ID, data.Phone, data.Name, data.Password, 0); err != nil {
+		if _, err := db.Conn(ctx, ur.db).Exec(ctx, sql, data.ID, data.Phone, data.Name, data.Password, 0); err != nil {
 			return err
 		}
 	case "email":
 		sql = `INSERT INTO users (id,email,name,password,friend_count,created_at) VALUES ($1,$2,$3,$4,$5,now())`
-		if _, err := ur.db.Exec(ctx, sql, data.ID, data.Email, data.Name, data.Password, 0); err != nil {
+		if _, err := db.Conn(ctx, ur.db).Exec(ctx, sql, data.ID, data.Email, data.Name, data.Password, 0); err != nil {
 			return err
 		}
 	}
@@ -143,7 +213,7 @@ func (ur *UserRepository) Delete(ctx context.Context, userId string) error {
 func (ur *UserRepository) Update(ctx context.Context, data entity.User) error {
 	sql := `UPDATE users SET name = $1, email = $2, phone = $3, password = $4, image_url = $5, friend_count = $6 WHERE id = $7`
 
-	_, err := ur.db.Exec(ctx, sql, data.Name, data.Email, data.Phone, data.Password, data.ImageUrl, data.FriendCount, data.ID)
+	_, err := db.Conn(ctx, ur.db).Exec(ctx, sql, data.Name, data.Email, data.Phone, data.Password, data.ImageUrl, data.FriendCount, data.ID)
 	if err != nil {
 		return err
 	}
@@ -153,7 +223,7 @@ func (ur *UserRepository) Update(ctx context.Context, data entity.User) error {
 func (ur *UserRepository) EmailCheck(ctx context.Context, email string) (int64, error) {
 	var count int64
 
-	if err := ur.db.QueryRow(ctx, "SELECT COUNT(id) FROM users WHERE email = $1", email).Scan(&count); err != nil {
+	if err := db.Conn(ctx, ur.db).QueryRow(ctx, "SELECT COUNT(id) FROM users WHERE email = $1", email).Scan(&count); err != nil {
 		return 0, nil
 	}
 
@@ -163,7 +233,7 @@ func (ur *UserRepository) EmailCheck(ctx context.Context, email string) (int64,
 func (ur *UserRepository) PhoneCheck(ctx context.Context, phone string) (int64, error) {
 	var count int64
 
-	if err := ur.db.QueryRow(ctx, "SELECT COUNT(id) FROM users WHERE phone = $1", phone).Scan(&count); err != nil {
+	if err := db.Conn(ctx, ur.db).QueryRow(ctx, "SELECT COUNT(id) FROM users WHERE phone = $1", phone).Scan(&count); err != nil {
 		return 0, nil
 	}
 
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Querier is the part of the pgx API shared by the pool and a transaction,
+// so repositories can run the same statements on either.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// Conn returns the transaction bound to ctx by WithinTransaction, or the pool
+// when ctx is not part of a transaction.
+func Conn(ctx context.Context, pool *pgxpool.Pool) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// WithinTransaction runs fn in a transaction that is committed when fn returns
+// nil and rolled back otherwise. Repository calls made with the ctx passed to
+// fn join the transaction. Nested calls reuse the outer transaction.
+func WithinTransaction(ctx context.Context, pool *pgxpool.Pool, fn func(context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Transactor lets handlers group several repository calls in one transaction.
+type Transactor struct {
+	db *pgxpool.Pool
+}
+
+func NewTransactor(db *pgxpool.Pool) *Transactor {
+	return &Transactor{
+		db: db,
+	}
+}
+
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return WithinTransaction(ctx, t.db, fn)
+}
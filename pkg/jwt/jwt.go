@@ -54,48 +54,17 @@ func JwtMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		tokenString := string(authHeader)
-		tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
-
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				fmt.Printf("unexpected signing method: %v \n", t.Header["alg"])
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-		if err != nil {
-			validationErr, ok := err.(*jwt.ValidationError)
-			if ok {
-				if validationErr.Errors == jwt.ValidationErrorExpired {
-					(&response.Response{
-						HttpStatus: http.StatusUnauthorized,
-						Message:    "given security scheme is valid, but the lifetime has been expired or revoked.",
-					}).GenerateResponse(w)
-					return
-				}
-			}
-			(&response.Response{
-				HttpStatus: http.StatusUnauthorized,
-				Message:    "token is invalid.",
-			}).GenerateResponse(w)
-			return
-		}
-
-		if !token.Valid {
-			(&response.Response{
-				HttpStatus: http.StatusUnauthorized,
-				Message:    "invalid token claims",
-			}).GenerateResponse(w)
+		r, ok := authenticate(w, r, authHeader)
+		if !ok {
 			return
 		}
-
-		ctx := context.WithValue(r.Context(), "user_id", token.Claims.(jwt.MapClaims)["user_id"])
-		r = r.WithContext(ctx)
 		next.ServeHTTP(w, r)
 	})
 }
 
+// OptionalJwtMiddleware lets requests without an Authorization header through
+// as anonymous. A header that is present must hold a valid token, the same as
+// for JwtMiddleware.
 func OptionalJwtMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -104,34 +73,54 @@ func OptionalJwtMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		tokenString := string(authHeader)
-		tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
-
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-		if err != nil {
-			validationErr, ok := err.(*jwt.ValidationError)
-			if ok {
-				if validationErr.Errors == jwt.ValidationErrorExpired {
-					next.ServeHTTP(w, r)
-					return
-				}
-			}
-			next.ServeHTTP(w, r)
+		r, ok := authenticate(w, r, authHeader)
+		if !ok {
 			return
 		}
+		next.ServeHTTP(w, r)
+	})
+}
 
-		if !token.Valid {
-			next.ServeHTTP(w, r)
-			return
+// authenticate verifies the token in authHeader and returns r carrying its
+// user id. An invalid or expired token is answered with 401 and reported with
+// ok false.
+func authenticate(w http.ResponseWriter, r *http.Request, authHeader string) (*http.Request, bool) {
+	tokenString := string(authHeader)
+	tokenString = strings.Replace(tokenString, "Bearer ", "", 1)
+
+	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			fmt.Printf("unexpected signing method: %v \n", t.Header["alg"])
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-
-		ctx := context.WithValue(r.Context(), "user_id", token.Claims.(jwt.MapClaims)["user_id"])
-		r = r.WithContext(ctx)
-		next.ServeHTTP(w, r)
+		return []byte(os.Getenv("JWT_SECRET")), nil
 	})
+	if err != nil {
+		validationErr, ok := err.(*jwt.ValidationError)
+		if ok {
+			if validationErr.Errors == jwt.ValidationErrorExpired {
+				(&response.Response{
+					HttpStatus: http.StatusUnauthorized,
+					Message:    "given security scheme is valid, but the lifetime has been expired or revoked.",
+				}).GenerateResponse(w)
+				return r, false
+			}
+		}
+		(&response.Response{
+			HttpStatus: http.StatusUnauthorized,
+			Message:    "token is invalid.",
+		}).GenerateResponse(w)
+		return r, false
+	}
+
+	if !token.Valid {
+		(&response.Response{
+			HttpStatus: http.StatusUnauthorized,
+			Message:    "invalid token claims",
+		}).GenerateResponse(w)
+		return r, false
+	}
+
+	ctx := context.WithValue(r.Context(), "user_id", token.Claims.(jwt.MapClaims)["user_id"])
+	return r.WithContext(ctx), true
 }
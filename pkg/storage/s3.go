@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/shafaalafghany/segokuning-social-app/config"
+)
+
+// S3Storage stores uploaded files as public objects in a single bucket.
+type S3Storage struct {
+	bucketName string
+	svc        *s3.S3
+}
+
+func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
+	ses, err := session.NewSession(&aws.Config{
+		Region: aws.String(cfg.Region),
+		Credentials: credentials.NewStaticCredentials(
+			cfg.ID,
+			cfg.SecretKey,
+			"",
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Storage{
+		bucketName: cfg.BucketName,
+		svc:        s3.New(ses),
+	}, nil
+}
+
+// Upload stores body under a randomized name derived from fileName and
+// returns its public url.
+func (s *S3Storage) Upload(fileName string, body io.ReadSeeker) (string, error) {
+	fileName = generateRandomString(10) + time.Now().Format("20060102150405") + "-" + fileName
+
+	_, err := s.svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(fileName),
+		Body:   body,
+		ACL:    aws.String("public-read"),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.urlPrefix() + fileName, nil
+}
+
+// Delete removes the object behind a url returned by Upload.
+func (s *S3Storage) Delete(url string) error {
+	key, ok := strings.CutPrefix(url, s.urlPrefix())
+	if !ok {
+		return fmt.Errorf("storage: %s is not stored in bucket %s", url, s.bucketName)
+	}
+
+	_, err := s.svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *S3Storage) urlPrefix() string {
+	return fmt.Sprintf("https://%s.s3-%s.amazonaws.com/", s.bucketName, "ap-southeast-1")
+}
+
+func generateRandomString(length int) string {
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(bytes)
+}